@@ -0,0 +1,436 @@
+// Package arrowtable converts tables to and from Apache Arrow records,
+// Arrow IPC streams, and Parquet files.
+//
+// Columns are mapped onto Arrow types as follows. Signed and unsigned
+// integers are read as integers, floats as floats, booleans as
+// booleans, strings as strings, timestamps and dates as time stamps,
+// and the null type as a column of missing values. Integers, floats,
+// and strings are written as 64-bit integers, 64-bit floats, and
+// strings. Time stamps are written as timestamps in nanoseconds, or in
+// microseconds if a time stamp is out of range, in the time zone shared
+// by the column's time stamps or UTC otherwise. The format of each time
+// stamp column is kept in the field's metadata.
+package arrowtable
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/ipc"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/apache/arrow/go/v10/parquet"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/nathangreene3/table"
+)
+
+// formatKey is the field metadata key holding the format of a time
+// stamp column.
+const formatKey = "table.format"
+
+// FromRecords returns a new table holding the rows of several records
+// sharing the same schema.
+func FromRecords(schema *arrow.Schema, recs ...arrow.Record) (*table.Table, error) {
+	var (
+		fields = schema.Fields()
+		cols   = make([]table.Column, len(fields))
+	)
+
+	for _, rec := range recs {
+		if !rec.Schema().Equal(schema) {
+			return nil, table.ErrDims
+		}
+
+		for j := 0; j < len(fields); j++ {
+			c, err := appendColumn(cols[j], rec.Column(j), fields[j])
+			if err != nil {
+				return nil, &table.Error{Err: err, Row: -1, Col: j, Name: fields[j].Name, Type: table.Inv, Value: fields[j].Type.String()}
+			}
+
+			cols[j] = c
+		}
+	}
+
+	t := table.New(nil)
+	for j := 0; j < len(fields); j++ {
+		if err := t.TryAppendCol(fields[j].Name, cols[j]); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// appendColumn appends the values of an array to a column.
+func appendColumn(c table.Column, arr arrow.Array, field arrow.Field) (table.Column, error) {
+	var (
+		m   = arr.Len()
+		loc = time.UTC
+		f   = time.RFC3339Nano
+	)
+
+	if k := field.Metadata.FindKey(formatKey); 0 <= k {
+		f = field.Metadata.Values()[k]
+	}
+
+	switch dt := field.Type.(type) {
+	case *arrow.TimestampType:
+		if dt.TimeZone != "" {
+			var err error
+			if loc, err = location(dt.TimeZone); err != nil {
+				return nil, err
+			}
+		}
+	case *arrow.Date32Type, *arrow.Date64Type:
+		if field.Metadata.FindKey(formatKey) < 0 {
+			f = "2006-01-02"
+		}
+	}
+
+	for i := 0; i < m; i++ {
+		if arr.IsNull(i) || arr.DataType().ID() == arrow.NULL {
+			c = append(c, table.Null)
+			continue
+		}
+
+		switch a := arr.(type) {
+		case *array.Int8:
+			c = append(c, int(a.Value(i)))
+		case *array.Int16:
+			c = append(c, int(a.Value(i)))
+		case *array.Int32:
+			c = append(c, int(a.Value(i)))
+		case *array.Int64:
+			c = append(c, int(a.Value(i)))
+		case *array.Uint8:
+			c = append(c, int(a.Value(i)))
+		case *array.Uint16:
+			c = append(c, int(a.Value(i)))
+		case *array.Uint32:
+			c = append(c, int(a.Value(i)))
+		case *array.Uint64:
+			if math.MaxInt < a.Value(i) {
+				return nil, table.ErrRange
+			}
+
+			c = append(c, int(a.Value(i)))
+		case *array.Float32:
+			// Use the shortest decimal representation of the float32
+			x, _ := strconv.ParseFloat(strconv.FormatFloat(float64(a.Value(i)), 'g', -1, 32), 64)
+			c = append(c, x)
+		case *array.Float64:
+			c = append(c, a.Value(i))
+		case *array.Boolean:
+			c = append(c, a.Value(i))
+		case *array.String:
+			c = append(c, a.Value(i))
+		case *array.LargeString:
+			c = append(c, a.Value(i))
+		case *array.Timestamp:
+			c = append(c, table.NewFTime(timestamp(a.Value(i), field.Type.(*arrow.TimestampType).Unit).In(loc), f))
+		case *array.Date32:
+			c = append(c, table.NewFTime(a.Value(i).ToTime(), f))
+		case *array.Date64:
+			c = append(c, table.NewFTime(a.Value(i).ToTime(), f))
+		default:
+			return nil, table.ErrType
+		}
+	}
+
+	return c, nil
+}
+
+// timestamp returns the time of a timestamp of a given unit. Unlike
+// Timestamp.ToTime, this does not overflow for times outside the range
+// of nanoseconds.
+func timestamp(ts arrow.Timestamp, unit arrow.TimeUnit) time.Time {
+	switch unit {
+	case arrow.Second:
+		return time.Unix(int64(ts), 0).UTC()
+	case arrow.Millisecond:
+		return time.UnixMilli(int64(ts)).UTC()
+	case arrow.Microsecond:
+		return time.UnixMicro(int64(ts)).UTC()
+	default:
+		return time.Unix(0, int64(ts)).UTC()
+	}
+}
+
+// ToRecord returns a new record holding the rows of a table. The record
+// should be released when it is no longer needed.
+func ToRecord(t *table.Table, mem memory.Allocator) arrow.Record {
+	var (
+		m, n   = t.Dims()
+		h      = t.Header()
+		ts     = t.ColTypes()
+		fields = make([]arrow.Field, 0, n)
+		arrs   = make([]arrow.Array, 0, n)
+	)
+
+	for j := 0; j < n; j++ {
+		field := arrow.Field{Name: h[j], Nullable: true}
+		switch ts[j] {
+		case table.Int:
+			b := array.NewInt64Builder(mem)
+			ints, valid := t.ColNullInts(j)
+			for i := 0; i < m; i++ {
+				if valid[i] {
+					b.Append(int64(ints[i]))
+				} else {
+					b.AppendNull()
+				}
+			}
+
+			field.Type = arrow.PrimitiveTypes.Int64
+			arrs = append(arrs, b.NewArray())
+			b.Release()
+		case table.Flt:
+			b := array.NewFloat64Builder(mem)
+			b.AppendValues(t.ColNullFloats(j))
+			field.Type = arrow.PrimitiveTypes.Float64
+			arrs = append(arrs, b.NewArray())
+			b.Release()
+		case table.Bool:
+			b := array.NewBooleanBuilder(mem)
+			b.AppendValues(t.ColNullBools(j))
+			field.Type = arrow.FixedWidthTypes.Boolean
+			arrs = append(arrs, b.NewArray())
+			b.Release()
+		case table.Time:
+			dt, md := timestampType(t, j)
+			b := array.NewTimestampBuilder(mem, dt)
+			times, valid := t.ColNullTimes(j)
+			for i := 0; i < m; i++ {
+				switch {
+				case !valid[i]:
+					b.AppendNull()
+				case dt.Unit == arrow.Nanosecond:
+					b.Append(arrow.Timestamp(times[i].UnixNano()))
+				default:
+					b.Append(arrow.Timestamp(times[i].UnixMicro()))
+				}
+			}
+
+			field.Type, field.Metadata = dt, md
+			arrs = append(arrs, b.NewArray())
+			b.Release()
+		case table.Str:
+			b := array.NewStringBuilder(mem)
+			b.AppendValues(t.ColNullStrs(j))
+			field.Type = arrow.BinaryTypes.String
+			arrs = append(arrs, b.NewArray())
+			b.Release()
+		default:
+			field.Type = arrow.Null
+			arrs = append(arrs, array.NewNull(m))
+		}
+
+		fields = append(fields, field)
+	}
+
+	rec := array.NewRecord(arrow.NewSchema(fields, nil), arrs, int64(m))
+	for _, arr := range arrs {
+		arr.Release()
+	}
+
+	return rec
+}
+
+// timestampType returns the timestamp type and field metadata of the
+// jth column of time stamps.
+func timestampType(t *table.Table, j int) (*arrow.TimestampType, arrow.Metadata) {
+	var (
+		m, _ = t.Dims()
+		dt   = arrow.TimestampType{Unit: arrow.Nanosecond}
+		f    string
+	)
+
+	for i := 0; i < m; i++ {
+		if t.IsNull(i, j) {
+			continue
+		}
+
+		ft := t.Value(i, j).(table.FTime)
+		tm := ft.Time()
+		if f == "" {
+			f, dt.TimeZone = ft.Format(), timeZone(tm)
+		} else if dt.TimeZone != timeZone(tm) {
+			dt.TimeZone = "UTC"
+		}
+
+		if !time.Unix(0, tm.UnixNano()).Equal(tm) {
+			dt.Unit = arrow.Microsecond
+		}
+	}
+
+	if dt.TimeZone == "" || dt.TimeZone == "Local" {
+		dt.TimeZone = "UTC"
+	}
+
+	if f == "" {
+		f = time.RFC3339Nano
+	}
+
+	return &dt, arrow.NewMetadata([]string{formatKey}, []string{f})
+}
+
+// timeZone returns the Arrow time zone of a time. This is the name of
+// its location if the name is a time zone database name giving the
+// same offset. Otherwise, such as for abbreviations like "PDT" and
+// fixed zones, it is the numeric offset "+hh:mm".
+func timeZone(tm time.Time) string {
+	name := tm.Location().String()
+	if name == "Local" {
+		return name
+	}
+
+	_, offset := tm.Zone()
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		if _, o := tm.In(loc).Zone(); o == offset {
+			return name
+		}
+	}
+
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset/60%60)
+}
+
+// location returns the location of an Arrow time zone, which is either
+// a numeric offset "±hh:mm" or a time zone database name.
+func location(tz string) (*time.Location, error) {
+	if len(tz) == 6 && (tz[0] == '+' || tz[0] == '-') && tz[3] == ':' {
+		h, err0 := strconv.Atoi(tz[1:3])
+		m, err1 := strconv.Atoi(tz[4:])
+		if err0 == nil && err1 == nil {
+			offset := h*3600 + m*60
+			if tz[0] == '-' {
+				offset = -offset
+			}
+
+			return time.FixedZone(tz, offset), nil
+		}
+	}
+
+	return time.LoadLocation(tz)
+}
+
+// FromIPCReader returns a new table with data read from an Arrow IPC
+// stream.
+func FromIPCReader(r io.Reader) (*table.Table, error) {
+	rdr, err := ipc.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rdr.Release()
+	var recs []arrow.Record
+	for rdr.Next() {
+		rec := rdr.Record()
+		rec.Retain()
+		defer rec.Release()
+		recs = append(recs, rec)
+	}
+
+	if err := rdr.Err(); err != nil {
+		return nil, err
+	}
+
+	return FromRecords(rdr.Schema(), recs...)
+}
+
+// WriteIPCTo writes a table to a writer as an Arrow IPC stream holding
+// one record.
+func WriteIPCTo(w io.Writer, t *table.Table) error {
+	rec := ToRecord(t, memory.DefaultAllocator)
+	defer rec.Release()
+
+	iw := ipc.NewWriter(w, ipc.WithSchema(rec.Schema()))
+	if err := iw.Write(rec); err != nil {
+		iw.Close()
+		return err
+	}
+
+	return iw.Close()
+}
+
+// FromParquet returns a new table with data read from a Parquet file.
+func FromParquet(fileName string) (*table.Table, error) {
+	f, err := os.Open(filepath.Clean(fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	return FromParquetReader(f)
+}
+
+// FromParquetReader returns a new table with data read from a reader of
+// a Parquet file.
+func FromParquetReader(r parquet.ReaderAtSeeker) (*table.Table, error) {
+	tbl, err := pqarrow.ReadTable(context.Background(), r, nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tbl.Release()
+	tr := array.NewTableReader(tbl, -1)
+	defer tr.Release()
+
+	var recs []arrow.Record
+	for tr.Next() {
+		rec := tr.Record()
+		rec.Retain()
+		defer rec.Release()
+		recs = append(recs, rec)
+	}
+
+	return FromRecords(tbl.Schema(), recs...)
+}
+
+// WriteParquet writes a table to a Parquet file. If the file exists, it
+// is truncated.
+func WriteParquet(fileName string, t *table.Table) error {
+	f, err := os.Create(filepath.Clean(fileName))
+	if err != nil {
+		return err
+	}
+
+	if err := WriteParquetTo(f, t); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// WriteParquetTo writes a table to a writer as a Parquet file. The
+// Arrow schema is stored in the file so time zones and formats are
+// kept.
+func WriteParquetTo(w io.Writer, t *table.Table) error {
+	rec := ToRecord(t, memory.DefaultAllocator)
+	defer rec.Release()
+
+	// Hide any Close method so the writer is not closed
+	fw, err := pqarrow.NewFileWriter(rec.Schema(), struct{ io.Writer }{w}, nil, pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+	if err != nil {
+		return err
+	}
+
+	if err := fw.Write(rec); err != nil {
+		fw.Close()
+		return err
+	}
+
+	return fw.Close()
+}
@@ -0,0 +1,149 @@
+package arrowtable
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/nathangreene3/table"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var (
+		when = time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+		exp  = table.New(
+			table.NewHeader("id", "score", "ok", "when", "name", "none"),
+			table.NewRow(1, 1.5, true, table.NewFTime(when, "2006-01-02 15:04:05.999999999"), "a", table.Null),
+			table.NewRow(2, table.Null, false, table.Null, table.Null, table.Null),
+			table.NewRow(table.Null, 2.0, table.Null, table.NewFTime(when, "2006-01-02 15:04:05.999999999"), "c", table.Null),
+		)
+	)
+
+	var buf bytes.Buffer
+	if err := WriteIPCTo(&buf, exp); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := FromIPCReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	buf.Reset()
+	if err := WriteParquetTo(&buf, exp); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec, err = FromParquetReader(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	// Time stamps out of the range of nanoseconds
+	exp = table.New(table.NewHeader("when"), table.NewRow(table.NewFTime(time.Time{})))
+	buf.Reset()
+	if err := WriteParquetTo(&buf, exp); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec, err = FromParquetReader(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+}
+
+func TestTimeZones(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	const f = "2006-01-02 15:04:05 -07:00"
+	when := time.Date(2021, 7, 4, 5, 6, 7, 0, time.UTC)
+	for _, loc := range []*time.Location{
+		time.FixedZone("", 5*3600),
+		time.FixedZone("MST", 0),
+		time.FixedZone("PDT", -7*3600),
+		time.FixedZone("+05:30", 5*3600+30*60),
+		ny,
+	} {
+		exp := table.New(table.NewHeader("when"), table.NewRow(table.NewFTime(when.In(loc), f)))
+
+		var buf bytes.Buffer
+		if err := WriteIPCTo(&buf, exp); err != nil {
+			t.Fatal(err)
+		}
+
+		rec, err := FromIPCReader(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ft, ok := rec.Value(0, 0).(table.FTime); !ok || !ft.Equal(exp.Value(0, 0).(table.FTime)) || ft.String() != exp.Value(0, 0).(table.FTime).String() {
+			t.Fatalf("\n%q: expected\n%s\nreceived\n%s\n", loc, exp, rec)
+		}
+
+		buf.Reset()
+		if err := WriteParquetTo(&buf, exp); err != nil {
+			t.Fatal(err)
+		}
+
+		if rec, err = FromParquetReader(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatal(err)
+		}
+
+		if ft, ok := rec.Value(0, 0).(table.FTime); !ok || !ft.Equal(exp.Value(0, 0).(table.FTime)) || ft.String() != exp.Value(0, 0).(table.FTime).String() {
+			t.Fatalf("\n%q: expected\n%s\nreceived\n%s\n", loc, exp, rec)
+		}
+	}
+}
+
+func TestFromRecords(t *testing.T) {
+	var (
+		mem    = memory.DefaultAllocator
+		schema = arrow.NewSchema(
+			[]arrow.Field{
+				{Name: "i32", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+				{Name: "f32", Type: arrow.PrimitiveTypes.Float32},
+				{Name: "date", Type: arrow.FixedWidthTypes.Date32},
+			},
+			nil,
+		)
+		b = array.NewRecordBuilder(mem, schema)
+	)
+
+	defer b.Release()
+	b.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 0}, []bool{true, false})
+	b.Field(1).(*array.Float32Builder).AppendValues([]float32{1.1, 2.5}, nil)
+	b.Field(2).(*array.Date32Builder).AppendValues([]arrow.Date32{18690, 18691}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	tbl, err := FromRecords(schema, rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := table.New(
+		table.NewHeader("i32", "f32", "date"),
+		table.NewRow(1, 1.1, table.NewFTime(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), "2006-01-02")),
+		table.NewRow(table.Null, 2.5, table.NewFTime(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC), "2006-01-02")),
+	)
+
+	if !exp.Equal(tbl) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, tbl)
+	}
+}
@@ -1,6 +1,7 @@
 package table
 
 import (
+	"math"
 	"strconv"
 	"strings"
 )
@@ -55,6 +56,8 @@ func (b Body) String() string {
 				sb.WriteString(" " + b[i].(FTime).String())
 			case Str:
 				sb.WriteString(" " + b[i].(string))
+			case Nil:
+				sb.WriteString(" null")
 			default:
 				panic(errType)
 			}
@@ -69,29 +72,12 @@ func (b Body) String() string {
 }
 
 // Strings returns a list of strings in which each string is the string
-// value converted to string by parsing the type. If a value does not
-// parse, the empty string will be inserted.
+// value converted to string by parsing the type. Missing values are
+// converted to the empty string.
 func (b Body) Strings() []string {
 	ss := make([]string, 0, len(b))
 	for i := 0; i < len(b); i++ {
-		switch Parse(b[i]) {
-		case Int:
-			ss = append(ss, strconv.Itoa(b[i].(int)))
-		case Flt:
-			if v := b[i].(float64); v == float64(int(v)) {
-				ss = append(ss, strconv.FormatFloat(v, 'f', 1, 64)) // Forces f.0 when value is an integer
-			} else {
-				ss = append(ss, strconv.FormatFloat(v, 'f', -1, 64))
-			}
-		case Bool:
-			ss = append(ss, strconv.FormatBool(b[i].(bool)))
-		case Time:
-			ss = append(ss, b[i].(FTime).String())
-		case Str:
-			ss = append(ss, b[i].(string))
-		default:
-			panic(errType)
-		}
+		ss = append(ss, formatValue(b[i]))
 	}
 
 	return ss
@@ -106,3 +92,34 @@ func (b Body) Types() Types {
 
 	return ts
 }
+
+// formatFloat returns the shortest representation of a float. Floats
+// having integer values end in ".0".
+func formatFloat(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return strconv.FormatFloat(f, 'f', 1, 64) // Forces f.0 when value is an integer
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatValue returns a value converted to string by parsing the type.
+// Missing values are converted to the empty string.
+func formatValue(v interface{}) string {
+	switch Parse(v) {
+	case Int:
+		return strconv.Itoa(v.(int))
+	case Flt:
+		return formatFloat(v.(float64))
+	case Bool:
+		return strconv.FormatBool(v.(bool))
+	case Time:
+		return v.(FTime).String()
+	case Str:
+		return v.(string)
+	case Nil:
+		return ""
+	default:
+		panic(errType)
+	}
+}
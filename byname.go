@@ -0,0 +1,224 @@
+package table
+
+import (
+	"time"
+)
+
+// ColIndex returns the index of the column named s. An error is
+// returned if no column or several columns are named s.
+func (t *Table) ColIndex(s string) (int, error) {
+	return t.colIndex(s)
+}
+
+// ColIndexFold returns the index of the column named s without regard
+// to case. An error is returned if no column or several columns are
+// named s.
+func (t *Table) ColIndexFold(s string) (int, error) {
+	j, err := t.header.IndexFold(s)
+	if err != nil {
+		return -1, &Error{Err: err, Row: -1, Col: -1, Name: s, Type: Inv}
+	}
+
+	return j, nil
+}
+
+// ColByName returns the column named s.
+func (t *Table) ColByName(s string) (Column, error) {
+	j, err := t.colIndex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Col(j), nil
+}
+
+// ColBoolsByName returns the column named s with each value cast as a
+// boolean. Missing values are returned as the zero value.
+func (t *Table) ColBoolsByName(s string) ([]bool, error) {
+	j, err := t.colOfType(s, Bool)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ColBools(j), nil
+}
+
+// ColFloatsByName returns the column named s with each value cast as a
+// float. Missing values are returned as the zero value.
+func (t *Table) ColFloatsByName(s string) ([]float64, error) {
+	j, err := t.colOfType(s, Flt)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ColFloats(j), nil
+}
+
+// ColIntsByName returns the column named s with each value cast as an
+// integer. Missing values are returned as the zero value.
+func (t *Table) ColIntsByName(s string) ([]int, error) {
+	j, err := t.colOfType(s, Int)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ColInts(j), nil
+}
+
+// ColStrsByName returns the column named s with each value cast as a
+// string. Missing values are returned as the zero value.
+func (t *Table) ColStrsByName(s string) ([]string, error) {
+	j, err := t.colOfType(s, Str)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ColStrs(j), nil
+}
+
+// ColTimesByName returns the column named s with each value cast as a
+// time object. Missing values are returned as the zero value.
+func (t *Table) ColTimesByName(s string) ([]time.Time, error) {
+	j, err := t.colOfType(s, Time)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ColTimes(j), nil
+}
+
+// ColTypeByName returns the type of the column named s.
+func (t *Table) ColTypeByName(s string) (Type, error) {
+	j, err := t.colIndex(s)
+	if err != nil {
+		return Inv, err
+	}
+
+	return t.cols[j].typ, nil
+}
+
+// colIndex returns the index of the column named s. The error returned
+// if no column or several columns are named s holds the name.
+func (t *Table) colIndex(s string) (int, error) {
+	j, err := t.header.Index(s)
+	if err != nil {
+		return -1, &Error{Err: err, Row: -1, Col: -1, Name: s, Type: Inv}
+	}
+
+	return j, nil
+}
+
+// colOfType returns the index of the column named s. An error is
+// returned if the column is neither of the given type nor of type Nil.
+// Its Value is the column's type.
+func (t *Table) colOfType(s string, tp Type) (int, error) {
+	j, err := t.colIndex(s)
+	if err != nil {
+		return -1, err
+	}
+
+	if t.cols[j].typ != tp && t.cols[j].typ != Nil {
+		return -1, &Error{Err: ErrType, Row: -1, Col: j, Name: s, Type: tp, Value: t.cols[j].typ}
+	}
+
+	return j, nil
+}
+
+// RemoveColByName removes and returns the column named s.
+func (t *Table) RemoveColByName(s string) (Column, error) {
+	j, err := t.colIndex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	_, c := t.RemoveCol(j)
+	return c, nil
+}
+
+// RenameCol renames the column named s.
+func (t *Table) RenameCol(s, newName string) error {
+	j, err := t.colIndex(s)
+	if err != nil {
+		return err
+	}
+
+	t.header[j] = newName
+	return nil
+}
+
+// SetByName sets the ith value in the column named s. A missing value
+// may be set in any column.
+func (t *Table) SetByName(i int, s string, v interface{}) error {
+	j, err := t.colIndex(s)
+	if err != nil {
+		return err
+	}
+
+	return t.TrySet(i, j, v)
+}
+
+// SortByName sorts a table on several columns as SortBy does. Each
+// key's column is identified by its name rather than its index.
+func (t *Table) SortByName(keys ...SortKey) error {
+	keys, err := t.resolve(keys)
+	if err != nil {
+		return err
+	}
+
+	t.SortBy(keys...)
+	return nil
+}
+
+// StableByName sorts a table on several columns as StableBy does. Each
+// key's column is identified by its name rather than its index.
+func (t *Table) StableByName(keys ...SortKey) error {
+	keys, err := t.resolve(keys)
+	if err != nil {
+		return err
+	}
+
+	t.StableBy(keys...)
+	return nil
+}
+
+// resolve returns a copy of a list of sort keys with each column index
+// set to the index of the named column.
+func (t *Table) resolve(keys []SortKey) ([]SortKey, error) {
+	resolved := append(make([]SortKey, 0, len(keys)), keys...)
+	for x := 0; x < len(resolved); x++ {
+		j, err := t.colIndex(resolved[x].Name)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved[x].Col = j
+	}
+
+	return resolved, nil
+}
+
+// SwapColsByName swaps the columns named s0 and s1.
+func (t *Table) SwapColsByName(s0, s1 string) error {
+	i, err := t.colIndex(s0)
+	if err != nil {
+		return err
+	}
+
+	j, err := t.colIndex(s1)
+	if err != nil {
+		return err
+	}
+
+	t.SwapCols(i, j)
+	return nil
+}
+
+// ValueByName returns the ith value in the column named s.
+func (t *Table) ValueByName(i int, s string) (interface{}, error) {
+	j, err := t.colIndex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.cols[j].value(i), nil
+}
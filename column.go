@@ -3,17 +3,31 @@ package table
 // Column is a list of values.
 type Column []interface{}
 
-// NewCol returns a new column of values.
+// NewCol returns a new column of values. This panics if the values
+// are not of the same type.
 func NewCol(values ...interface{}) Column {
-	if 0 < len(values) {
-		for i, f := 1, Parse(values[0]); i < len(values); i++ {
-			if f != Parse(values[i]) {
-				panic("invalid format")
-			}
+	c, err := TryNewCol(values...)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// TryNewCol returns a new column of values. An error is returned if
+// the values are not of the same type.
+func TryNewCol(values ...interface{}) (Column, error) {
+	tp := Nil
+	for i := 0; i < len(values); i++ {
+		next := unify(tp, Parse(values[i]))
+		if next == Inv {
+			return nil, &Error{Err: ErrType, Row: i, Col: -1, Type: tp, Value: values[i]}
 		}
+
+		tp = next
 	}
 
-	return append(make(Column, 0, len(values)), values...)
+	return append(make(Column, 0, len(values)), values...), nil
 }
 
 // Copy returns a copy of a column.
@@ -37,17 +51,13 @@ func (c Column) Equal(col Column) bool {
 	return true
 }
 
-// Type returns the type of the column.
+// Type returns the type of the column. Missing values are ignored, so
+// an empty column or a column holding only missing values is of type
+// Nil. If the values are of differing types, Inv is returned.
 func (c Column) Type() Type {
-	var t Type
-	if 0 < len(c) {
-		t = Parse(c[0])
-		for i := 1; i < len(c); i++ {
-			if Parse(c[i]) != t {
-				t = 0
-				break
-			}
-		}
+	t := Nil
+	for i := 0; i < len(c) && t != Inv; i++ {
+		t = unify(t, Parse(c[i]))
 	}
 
 	return t
@@ -0,0 +1,328 @@
+package table
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions holds settings for reading and writing csv-encoded data.
+type CSVOptions struct {
+	// Comma is the field delimiter. If zero, ',' is used.
+	Comma rune
+
+	// Comment, if not zero, begins a line to be ignored.
+	Comment rune
+
+	// LazyQuotes allows quotes to appear in unquoted fields and
+	// non-doubled quotes to appear in quoted fields.
+	LazyQuotes bool
+
+	// NoHeader indicates the first record is a row rather than the
+	// header. When reading, columns are named by their index. When
+	// writing, the header is omitted.
+	NoHeader bool
+
+	// QuoteAll quotes every written field. Otherwise, only fields
+	// requiring quotes are quoted.
+	QuoteAll bool
+
+	// UseCRLF ends each written line with \r\n rather than \n.
+	UseCRLF bool
+
+	// Null is written in place of missing values. When reading,
+	// fields equal to Null are read as missing values.
+	Null string
+
+	// Formatters, if provided, converts the values of each column to
+	// strings when writing. Columns without a formatter are written
+	// as the values' default string representation. Missing values
+	// are not passed to formatters.
+	Formatters []Formatter
+
+	// Types, if provided, holds the type of each column. Fields in a
+	// column of type Inv are parsed by guessing the type as int,
+	// float, bool, time, then string. A guessed column of integers is
+	// converted to floats when a later field is a float, and a guessed
+	// column of booleans or times is converted to strings when a later
+	// field is neither.
+	Types Types
+
+	// TimeFmts are time formats tried before the default time formats
+	// when parsing time stamps.
+	TimeFmts []string
+}
+
+// FromCSVReader returns a new table with data read from a csv-encoded
+// reader. Records are read one at a time. A single set of options may
+// be passed. This panics if more than one set of options is provided.
+// Empty fields are read as missing values.
+func FromCSVReader(r io.Reader, opts ...CSVOptions) (*Table, error) {
+	var opt CSVOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	cr := csv.NewReader(r)
+	if opt.Comma != 0 {
+		cr.Comma = opt.Comma
+	}
+
+	cr.Comment = opt.Comment
+	cr.LazyQuotes = opt.LazyQuotes
+	cr.ReuseRecord = true
+
+	record, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return New(nil), nil
+		}
+
+		return nil, err
+	}
+
+	n := len(record)
+	if 0 < len(opt.Types) && len(opt.Types) != n {
+		return nil, ErrDims
+	}
+
+	h := make(Header, 0, n)
+	for j := 0; j < n; j++ {
+		if opt.NoHeader {
+			h = append(h, strconv.Itoa(j))
+		} else {
+			h = append(h, record[j])
+		}
+	}
+
+	t := New(h)
+	for j := 0; j < len(opt.Types); j++ {
+		switch opt.Types[j] {
+		case Inv:
+		case Int, Flt, Bool, Time, Str, Nil:
+			t.cols[j] = newVector(opt.Types[j], 0)
+		default:
+			return nil, ErrType
+		}
+	}
+
+	if !opt.NoHeader {
+		if record, err = cr.Read(); err != nil {
+			if err == io.EOF {
+				return t, nil
+			}
+
+			return nil, err
+		}
+	}
+
+	for i := 0; err == nil; record, err = cr.Read() {
+		row := make(Row, 0, n)
+		for j := 0; j < n; j++ {
+			if record[j] == opt.Null {
+				row = append(row, Null)
+				continue
+			}
+
+			v, err := parseField(record[j], t.cols[j].typ, opt.TimeFmts...)
+			if err != nil && (len(opt.Types) == 0 || opt.Types[j] == Inv) {
+				v, err = widenField(&t.cols[j], record[j])
+			}
+
+			if err != nil {
+				return nil, newError(err, t, i, j, record[j])
+			}
+
+			row = append(row, v)
+		}
+
+		t.Append(row)
+		i++
+	}
+
+	if err != io.EOF {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// parseField returns the value of a csv field in a column of a given
+// type. If the type is Inv or Nil, the type is guessed as int, float,
+// bool, time, then string. An empty field is a missing value.
+func parseField(field string, tp Type, timeFmts ...string) (interface{}, error) {
+	if field == "" {
+		return Null, nil
+	}
+
+	switch tp {
+	case Int:
+		n, err := strconv.ParseInt(field, 10, strconv.IntSize)
+		if err != nil {
+			return nil, ErrType
+		}
+
+		return int(n), nil
+	case Flt:
+		f, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, ErrType
+		}
+
+		return f, nil
+	case Bool:
+		b, err := strconv.ParseBool(field)
+		if err != nil {
+			return nil, ErrType
+		}
+
+		return b, nil
+	case Time:
+		return ParseFTime(field, timeFmts...)
+	case Str:
+		return field, nil
+	case Inv, Nil:
+		if n, err := strconv.ParseInt(field, 10, strconv.IntSize); err == nil {
+			return int(n), nil
+		} else if f, err := strconv.ParseFloat(field, 64); err == nil {
+			return f, nil
+		} else if b, err := strconv.ParseBool(field); err == nil {
+			return b, nil
+		} else if ft, err := ParseFTime(field, timeFmts...); err == nil {
+			return ft, nil
+		}
+
+		return field, nil
+	default:
+		return nil, ErrType
+	}
+}
+
+// widenField returns the value of a csv field that does not fit its
+// column after widening the column to hold it. Integers are widened to
+// floats, and booleans and times to strings. An error is returned if
+// the column may not be widened to hold the field.
+func widenField(v *vector, field string) (interface{}, error) {
+	switch v.typ {
+	case Int:
+		f, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, ErrType
+		}
+
+		v.widen(Flt)
+		return f, nil
+	case Bool, Time:
+		v.widen(Str)
+		return field, nil
+	default:
+		return nil, ErrType
+	}
+}
+
+// WriteCSVTo writes a table to a writer as csv-encoded data. A single
+// set of options may be passed. This panics if more than one set of
+// options is provided.
+func (t *Table) WriteCSVTo(w io.Writer, opts ...CSVOptions) error {
+	var opt CSVOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	if opt.Comma == 0 {
+		opt.Comma = ','
+	}
+
+	var (
+		m, n   = t.Dims()
+		bw     = bufio.NewWriter(w)
+		cw     = csv.NewWriter(bw)
+		record = make([]string, n)
+	)
+
+	cw.Comma = opt.Comma
+	cw.UseCRLF = opt.UseCRLF
+
+	// csv.Writer only quotes fields requiring quotes. A record holding a
+	// single empty field is quoted anyway, as an empty line is skipped
+	// when read.
+	write := func(record []string) error {
+		if !opt.QuoteAll && (len(record) != 1 || record[0] != "") {
+			return cw.Write(record)
+		}
+
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+
+		return writeQuotedCSVRecord(bw, record, opt.Comma, opt.UseCRLF)
+	}
+
+	if !opt.NoHeader {
+		copy(record, t.header)
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			switch {
+			case t.cols[j].nulls[i]:
+				record[j] = opt.Null
+			case j < len(opt.Formatters) && opt.Formatters[j] != nil:
+				record[j] = opt.Formatters[j](t.cols[j].value(i))
+			default:
+				record[j] = t.cols[j].str(i)
+			}
+		}
+
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeQuotedCSVRecord writes a list of fields, each quoted, as a
+// csv-encoded line. As in csv.Writer, quotes are doubled and, if useCRLF
+// is set, line breaks within fields are written as \r\n.
+func writeQuotedCSVRecord(w *bufio.Writer, record []string, comma rune, useCRLF bool) error {
+	eol := "\n"
+	if useCRLF {
+		eol = "\r\n"
+	}
+
+	for i := 0; i < len(record); i++ {
+		if 0 < i {
+			w.WriteRune(comma)
+		}
+
+		field := strings.ReplaceAll(record[i], `"`, `""`)
+		if useCRLF {
+			field = strings.ReplaceAll(strings.ReplaceAll(field, "\r", ""), "\n", eol)
+		}
+
+		w.WriteString(`"` + field + `"`)
+	}
+
+	_, err := w.WriteString(eol)
+	return err
+}
@@ -1,10 +1,37 @@
 package table
 
+import (
+	"errors"
+	"fmt"
+)
+
 const (
+	// errAggKind indicates an aggregation kind is not defined.
+	errAggKind = "invalid aggregation kind"
+
+	// errColDup indicates a column name matches several columns.
+	errColDup = "duplicate column name"
+
+	// errColName indicates a column name is not in a header.
+	errColName = "column name not found"
+
 	// errDims indicates a slice does not have the same length as
 	// another.
 	errDims = "dimension mismatch"
 
+	// errField indicates a struct field is not of a supported type.
+	errField = "unsupported field"
+
+	// errFormat indicates a format name is not defined.
+	errFormat = "format name not found"
+
+	// errJSON indicates a string is not valid json of the expected
+	// shape.
+	errJSON = "invalid json"
+
+	// errJoinKind indicates a join kind is not defined.
+	errJoinKind = "invalid join kind"
+
 	// errRange indicates an index is either too small or large to
 	// access a value in an indexible object.
 	errRange = "index out of range"
@@ -19,3 +46,92 @@ const (
 	// were provided.
 	errVarCount = "unexpected variadic argument provided"
 )
+
+// Sentinel errors returned, possibly wrapped in an Error, by operations
+// that fail. Compare against them using errors.Is.
+var (
+	ErrAggKind  = errors.New(errAggKind)
+	ErrColDup   = errors.New(errColDup)
+	ErrColName  = errors.New(errColName)
+	ErrDims     = errors.New(errDims)
+	ErrField    = errors.New(errField)
+	ErrFormat   = errors.New(errFormat)
+	ErrJSON     = errors.New(errJSON)
+	ErrJoinKind = errors.New(errJoinKind)
+	ErrRange    = errors.New(errRange)
+	ErrTimeFmt  = errors.New(errTimeFmt)
+	ErrType     = errors.New(errType)
+)
+
+// Error describes a value that could not be stored in or read from a
+// table. Row and Col are -1 when they do not apply.
+type Error struct {
+	// Err is the sentinel error describing the failure.
+	Err error
+
+	// Row is the index of the row.
+	Row int
+
+	// Col is the index of the column.
+	Col int
+
+	// Name is the column name, if known, or the format name not found.
+	Name string
+
+	// Type is the expected type.
+	Type Type
+
+	// Value is the offending value or, if a column is not of the
+	// expected type, the column's type.
+	Value interface{}
+}
+
+// newError returns a new error describing the (i,j)th value of a table.
+func newError(err error, t *Table, i, j int, v interface{}) *Error {
+	e := Error{Err: err, Row: i, Col: j, Type: Inv, Value: v}
+	if t != nil && 0 <= j && j < len(t.cols) {
+		e.Name = t.header[j]
+		e.Type = t.cols[j].typ
+	}
+
+	return &e
+}
+
+// Error returns a description of the error. This implements the error
+// interface.
+func (e *Error) Error() string {
+	s := e.Err.Error()
+	if 0 <= e.Row {
+		s += fmt.Sprintf(": row %d", e.Row)
+	}
+
+	switch {
+	case e.Err == ErrFormat:
+		s += fmt.Sprintf(": %q", e.Name)
+	case 0 <= e.Col && e.Name != "":
+		s += fmt.Sprintf(": column %d (%q)", e.Col, e.Name)
+	case 0 <= e.Col:
+		s += fmt.Sprintf(": column %d", e.Col)
+	case e.Name != "":
+		s += fmt.Sprintf(": column %q", e.Name)
+	}
+
+	if e.Err == ErrType {
+		received := Parse(e.Value).String()
+		switch tp, ok := e.Value.(Type); {
+		case ok:
+			received = tp.String()
+		case received == Inv.String():
+			received = fmt.Sprintf("%T", e.Value)
+		}
+
+		s += fmt.Sprintf(": expected %s, received %s", e.Type, received)
+	}
+
+	return s
+}
+
+// Unwrap returns the sentinel error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -1,5 +1,10 @@
 package table
 
+import (
+	"sort"
+	"strings"
+)
+
 var (
 	// Fmt0 ...
 	//  Int  Str
@@ -192,6 +197,293 @@ var (
 		RowMidDelim:   "|",
 		RowRightDelim: "|",
 	}
+
+	// FmtLight draws light box-drawing lines.
+	// ┌─────┬─────┐
+	// │ Int │ Str │
+	// ├─────┼─────┤
+	// │   0 │ a   │
+	// │   1 │ b   │
+	// └─────┴─────┘
+	FmtLight = Format{
+		UpperHoriz:           "─",
+		UpperLeftHorizDelim:  "┌",
+		UpperMidHorizDelim:   "┬",
+		UpperRightHorizDelim: "┐",
+
+		MiddleHoriz:           "─",
+		MiddleLeftHorizDelim:  "├",
+		MiddleMidHorizDelim:   "┼",
+		MiddleRightHorizDelim: "┤",
+
+		BottomHoriz:           "─",
+		BottomLeftHorizDelim:  "└",
+		BottomMidHorizDelim:   "┴",
+		BottomRightHorizDelim: "┘",
+
+		HeaderLeftDelim:  "│",
+		HeaderMidDelim:   "│",
+		HeaderRightDelim: "│",
+
+		RowLeftDelim:  "│",
+		RowMidDelim:   "│",
+		RowRightDelim: "│",
+	}
+
+	// FmtHeavy draws heavy box-drawing lines.
+	// ┏━━━━━┳━━━━━┓
+	// ┃ Int ┃ Str ┃
+	// ┣━━━━━╋━━━━━┫
+	// ┃   0 ┃ a   ┃
+	// ┃   1 ┃ b   ┃
+	// ┗━━━━━┻━━━━━┛
+	FmtHeavy = Format{
+		UpperHoriz:           "━",
+		UpperLeftHorizDelim:  "┏",
+		UpperMidHorizDelim:   "┳",
+		UpperRightHorizDelim: "┓",
+
+		MiddleHoriz:           "━",
+		MiddleLeftHorizDelim:  "┣",
+		MiddleMidHorizDelim:   "╋",
+		MiddleRightHorizDelim: "┫",
+
+		BottomHoriz:           "━",
+		BottomLeftHorizDelim:  "┗",
+		BottomMidHorizDelim:   "┻",
+		BottomRightHorizDelim: "┛",
+
+		HeaderLeftDelim:  "┃",
+		HeaderMidDelim:   "┃",
+		HeaderRightDelim: "┃",
+
+		RowLeftDelim:  "┃",
+		RowMidDelim:   "┃",
+		RowRightDelim: "┃",
+	}
+
+	// FmtDouble draws double box-drawing lines.
+	// ╔═════╦═════╗
+	// ║ Int ║ Str ║
+	// ╠═════╬═════╣
+	// ║   0 ║ a   ║
+	// ║   1 ║ b   ║
+	// ╚═════╩═════╝
+	FmtDouble = Format{
+		UpperHoriz:           "═",
+		UpperLeftHorizDelim:  "╔",
+		UpperMidHorizDelim:   "╦",
+		UpperRightHorizDelim: "╗",
+
+		MiddleHoriz:           "═",
+		MiddleLeftHorizDelim:  "╠",
+		MiddleMidHorizDelim:   "╬",
+		MiddleRightHorizDelim: "╣",
+
+		BottomHoriz:           "═",
+		BottomLeftHorizDelim:  "╚",
+		BottomMidHorizDelim:   "╩",
+		BottomRightHorizDelim: "╝",
+
+		HeaderLeftDelim:  "║",
+		HeaderMidDelim:   "║",
+		HeaderRightDelim: "║",
+
+		RowLeftDelim:  "║",
+		RowMidDelim:   "║",
+		RowRightDelim: "║",
+	}
+
+	// FmtRounded draws light box-drawing lines with rounded corners.
+	// ╭─────┬─────╮
+	// │ Int │ Str │
+	// ├─────┼─────┤
+	// │   0 │ a   │
+	// │   1 │ b   │
+	// ╰─────┴─────╯
+	FmtRounded = Format{
+		UpperHoriz:           "─",
+		UpperLeftHorizDelim:  "╭",
+		UpperMidHorizDelim:   "┬",
+		UpperRightHorizDelim: "╮",
+
+		MiddleHoriz:           "─",
+		MiddleLeftHorizDelim:  "├",
+		MiddleMidHorizDelim:   "┼",
+		MiddleRightHorizDelim: "┤",
+
+		BottomHoriz:           "─",
+		BottomLeftHorizDelim:  "╰",
+		BottomMidHorizDelim:   "┴",
+		BottomRightHorizDelim: "╯",
+
+		HeaderLeftDelim:  "│",
+		HeaderMidDelim:   "│",
+		HeaderRightDelim: "│",
+
+		RowLeftDelim:  "│",
+		RowMidDelim:   "│",
+		RowRightDelim: "│",
+	}
+
+	// FmtRSTGrid is a reStructuredText grid table.
+	// +-----+-----+
+	// | Int | Str |
+	// +=====+=====+
+	// |   0 | a   |
+	// +-----+-----+
+	// |   1 | b   |
+	// +-----+-----+
+	FmtRSTGrid = Format{
+		UpperHoriz:           "-",
+		UpperLeftHorizDelim:  "+",
+		UpperMidHorizDelim:   "+",
+		UpperRightHorizDelim: "+",
+
+		MiddleHoriz:           "=",
+		MiddleLeftHorizDelim:  "+",
+		MiddleMidHorizDelim:   "+",
+		MiddleRightHorizDelim: "+",
+
+		BottomHoriz:           "-",
+		BottomLeftHorizDelim:  "+",
+		BottomMidHorizDelim:   "+",
+		BottomRightHorizDelim: "+",
+
+		SepHoriz:           "-",
+		SepLeftHorizDelim:  "+",
+		SepMidHorizDelim:   "+",
+		SepRightHorizDelim: "+",
+
+		HeaderLeftDelim:  "|",
+		HeaderMidDelim:   "|",
+		HeaderRightDelim: "|",
+
+		RowLeftDelim:  "|",
+		RowMidDelim:   "|",
+		RowRightDelim: "|",
+	}
+
+	// FmtRSTSimple is a reStructuredText simple table.
+	// ===== =====
+	//  Int   Str
+	// ===== =====
+	//    0   a
+	//    1   b
+	// ===== =====
+	FmtRSTSimple = Format{
+		UpperHoriz:           "=",
+		UpperLeftHorizDelim:  "",
+		UpperMidHorizDelim:   " ",
+		UpperRightHorizDelim: "",
+
+		MiddleHoriz:           "=",
+		MiddleLeftHorizDelim:  "",
+		MiddleMidHorizDelim:   " ",
+		MiddleRightHorizDelim: "",
+
+		BottomHoriz:           "=",
+		BottomLeftHorizDelim:  "",
+		BottomMidHorizDelim:   " ",
+		BottomRightHorizDelim: "",
+
+		HeaderLeftDelim:  "",
+		HeaderMidDelim:   " ",
+		HeaderRightDelim: "",
+
+		RowLeftDelim:  "",
+		RowMidDelim:   " ",
+		RowRightDelim: "",
+	}
+
+	// FmtOrg is an Org mode table.
+	// | Int | Str |
+	// |-----+-----|
+	// |   0 | a   |
+	// |   1 | b   |
+	FmtOrg = Format{
+		UpperHoriz:           "",
+		UpperLeftHorizDelim:  "",
+		UpperMidHorizDelim:   "",
+		UpperRightHorizDelim: "",
+
+		MiddleHoriz:           "-",
+		MiddleLeftHorizDelim:  "|",
+		MiddleMidHorizDelim:   "+",
+		MiddleRightHorizDelim: "|",
+
+		BottomHoriz:           "",
+		BottomLeftHorizDelim:  "",
+		BottomMidHorizDelim:   "",
+		BottomRightHorizDelim: "",
+
+		HeaderLeftDelim:  "|",
+		HeaderMidDelim:   "|",
+		HeaderRightDelim: "|",
+
+		RowLeftDelim:  "|",
+		RowMidDelim:   "|",
+		RowRightDelim: "|",
+	}
+
+	// FmtPSQL resembles the output of psql.
+	//  Int | Str
+	// -----+-----
+	//    0 | a
+	//    1 | b
+	FmtPSQL = Format{
+		UpperHoriz:           "",
+		UpperLeftHorizDelim:  "",
+		UpperMidHorizDelim:   "",
+		UpperRightHorizDelim: "",
+
+		MiddleHoriz:           "-",
+		MiddleLeftHorizDelim:  "",
+		MiddleMidHorizDelim:   "+",
+		MiddleRightHorizDelim: "",
+
+		BottomHoriz:           "",
+		BottomLeftHorizDelim:  "",
+		BottomMidHorizDelim:   "",
+		BottomRightHorizDelim: "",
+
+		HeaderLeftDelim:  "",
+		HeaderMidDelim:   "|",
+		HeaderRightDelim: "",
+
+		RowLeftDelim:  "",
+		RowMidDelim:   "|",
+		RowRightDelim: "",
+	}
+
+	// FmtCompact draws no lines.
+	//  Int  Str
+	//    0  a
+	//    1  b
+	FmtCompact = Format{
+		UpperHoriz:           "",
+		UpperLeftHorizDelim:  "",
+		UpperMidHorizDelim:   "",
+		UpperRightHorizDelim: "",
+
+		MiddleHoriz:           "",
+		MiddleLeftHorizDelim:  "",
+		MiddleMidHorizDelim:   "",
+		MiddleRightHorizDelim: "",
+
+		BottomHoriz:           "",
+		BottomLeftHorizDelim:  "",
+		BottomMidHorizDelim:   "",
+		BottomRightHorizDelim: "",
+
+		HeaderLeftDelim:  "",
+		HeaderMidDelim:   "",
+		HeaderRightDelim: "",
+
+		RowLeftDelim:  "",
+		RowMidDelim:   "",
+		RowRightDelim: "",
+	}
 )
 
 // Format holds decoration characters for displaying a table.
@@ -211,6 +503,13 @@ type Format struct {
 	BottomMidHorizDelim   string
 	BottomRightHorizDelim string
 
+	// SepHoriz and its delimiters draw a line between consecutive
+	// rows. No line is drawn if SepHoriz is empty.
+	SepHoriz           string
+	SepLeftHorizDelim  string
+	SepMidHorizDelim   string
+	SepRightHorizDelim string
+
 	HeaderLeftDelim  string
 	HeaderMidDelim   string
 	HeaderRightDelim string
@@ -218,4 +517,108 @@ type Format struct {
 	RowLeftDelim  string
 	RowMidDelim   string
 	RowRightDelim string
+
+	// Null is displayed in place of missing values.
+	Null string
+}
+
+const (
+	// AlignDefault aligns integers and floats to the right and other
+	// values to the left.
+	AlignDefault Align = iota
+
+	// AlignLeft aligns values to the left.
+	AlignLeft
+
+	// AlignCenter centers values.
+	AlignCenter
+
+	// AlignRight aligns values to the right.
+	AlignRight
+)
+
+// Align determines how values are positioned within a column.
+type Align byte
+
+// ColFormat holds settings for displaying a column.
+type ColFormat struct {
+	// Align positions the header and values within the column.
+	Align Align
+
+	// MinWidth and MaxWidth limit the width of the column, not
+	// including the space on either side. Zero means no limit.
+	MinWidth, MaxWidth int
+
+	// Wrap breaks lines wider than MaxWidth between words onto
+	// several lines. Otherwise, they are truncated with an ellipsis.
+	Wrap bool
+
+	// Formatter, if provided, converts each value that is not missing
+	// to a string, such as a NumFormat's Formatter.
+	Formatter Formatter
+
+	// Point marks the decimal point on which numbers aligned right
+	// are lined up, such as "," for a NumFormat whose Sep is ".". If
+	// empty, "." is used.
+	Point string
+}
+
+// FormatOptions holds settings for displaying the columns of a table.
+type FormatOptions struct {
+	// Cols maps column names to settings. Columns not named use Default.
+	Cols map[string]ColFormat
+
+	// Default holds the settings of columns not named in Cols.
+	Default ColFormat
+
+	// Ellipsis ends truncated lines. If empty, "…" is used.
+	Ellipsis string
+
+	// SampleRows, if positive, is the number of leading rows measured
+	// to size the columns. Wider values in later rows are truncated,
+	// or wrapped if their column wraps. Otherwise, every row is
+	// measured.
+	SampleRows int
+
+	// NoLeadingNewline omits the newline preceding the table.
+	NoLeadingNewline bool
+
+	// NoTrailingNewline omits the newline ending the last line.
+	NoTrailingNewline bool
+}
+
+// formats are the formats that may be looked up by name.
+var formats = map[string]Format{
+	"ascii":      Fmt5,
+	"compact":    FmtCompact,
+	"double":     FmtDouble,
+	"heavy":      FmtHeavy,
+	"light":      FmtLight,
+	"org":        FmtOrg,
+	"psql":       FmtPSQL,
+	"rounded":    FmtRounded,
+	"rst-grid":   FmtRSTGrid,
+	"rst-simple": FmtRSTSimple,
+}
+
+// FormatByName returns the format having a given name, ignoring case.
+// The names are listed by FormatNames.
+func FormatByName(name string) (Format, error) {
+	if f, ok := formats[strings.ToLower(name)]; ok {
+		return f, nil
+	}
+
+	return Format{}, &Error{Err: ErrFormat, Row: -1, Col: -1, Name: name, Type: Inv}
+}
+
+// FormatNames returns the sorted names of the formats that may be
+// looked up by FormatByName.
+func FormatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
 }
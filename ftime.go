@@ -1,7 +1,6 @@
 package table
 
 import (
-	"errors"
 	"time"
 )
 
@@ -92,7 +91,7 @@ func ParseFTime(timeStr string, additionalTimeFmts ...string) (FTime, error) {
 		}
 	}
 
-	return FTime{}, errors.New(errTimeFmt)
+	return FTime{}, ErrTimeFmt
 }
 
 // String returns a timestamp formatted by the underlying
@@ -3,6 +3,8 @@ module github.com/nathangreene3/table
 go 1.17
 
 require (
+	github.com/mattn/go-runewidth v0.0.15
+	github.com/rivo/uniseg v0.2.0
 	github.com/tidwall/gjson v1.14.0
 	github.com/tidwall/sjson v1.2.4
 )
@@ -0,0 +1,321 @@
+package table
+
+import (
+	"strings"
+)
+
+const (
+	// AggCount counts the values that are not missing. If no column
+	// is named, the rows are counted.
+	AggCount AggKind = iota
+
+	// AggSum sums integers or floats.
+	AggSum
+
+	// AggMean averages integers or floats as a float.
+	AggMean
+
+	// AggMin returns the least value.
+	AggMin
+
+	// AggMax returns the greatest value.
+	AggMax
+
+	// AggFirst returns the first value that is not missing.
+	AggFirst
+
+	// AggLast returns the last value that is not missing.
+	AggLast
+
+	// AggDistinct counts the distinct values that are not missing.
+	AggDistinct
+
+	// AggConcat joins the values, converted to strings, separated by
+	// a separator.
+	AggConcat
+
+	// AggFunc applies a user-defined aggregator.
+	AggFunc
+)
+
+// aggNames are the default suffixes of aggregated column names.
+var aggNames = [...]string{"count", "sum", "mean", "min", "max", "first", "last", "distinct", "concat", "func"}
+
+type (
+	// AggKind determines how the values of a column within a group are
+	// combined into one value.
+	AggKind byte
+
+	// Aggregation defines a column of a grouped table.
+	Aggregation struct {
+		// Col names the column to aggregate.
+		Col string
+
+		// Kind determines how the values are aggregated.
+		Kind AggKind
+
+		// Func aggregates the values of a group when Kind is AggFunc.
+		Func Aggregator
+
+		// Sep separates values when Kind is AggConcat.
+		Sep string
+
+		// Name is the name of the aggregated column. If empty, the
+		// name is the column name and the kind joined by an
+		// underscore, such as "price_sum".
+		Name string
+	}
+
+	// Aggregator combines the values of a column within a group into
+	// one value.
+	Aggregator func(c Column) interface{}
+)
+
+// GroupBy returns a new table with one row per distinct combination of
+// values in the named key columns. The header consists of the key
+// columns followed by each aggregated column. Groups are ordered by
+// first appearance and missing keys are grouped together. Missing
+// values are ignored by every aggregation other than AggFunc. The sum,
+// mean, min, and max of a group of missing values are missing.
+func (t *Table) GroupBy(by []string, aggs ...Aggregation) (*Table, error) {
+	var (
+		m, _ = t.Dims()
+		ks   = make([]int, 0, len(by))   // Key column indices
+		as   = make([]int, 0, len(aggs)) // Aggregated column indices
+		h    = make(Header, 0, len(by)+len(aggs))
+		ts   = make(Types, 0, len(by)+len(aggs))
+	)
+
+	for _, name := range by {
+		j, err := t.colIndex(name)
+		if err != nil {
+			return nil, err
+		}
+
+		ks = append(ks, j)
+		h = append(h, name)
+		ts = append(ts, t.cols[j].typ)
+	}
+
+	for _, a := range aggs {
+		j := -1
+		if a.Col != "" || a.Kind != AggCount {
+			var err error
+			if j, err = t.colIndex(a.Col); err != nil {
+				return nil, err
+			}
+		}
+
+		tp, err := aggType(a, t.cols, j)
+		if err != nil {
+			return nil, err
+		}
+
+		name := a.Name
+		if name == "" {
+			name = aggNames[a.Kind]
+			if a.Col != "" {
+				name = a.Col + "_" + name
+			}
+		}
+
+		as = append(as, j)
+		h = append(h, name)
+		ts = append(ts, tp)
+	}
+
+	// Collect the rows of each group.
+	var (
+		index  = make(map[string]int)
+		groups [][]int
+		sb     strings.Builder
+	)
+
+	for i := 0; i < m; i++ {
+		sb.Reset()
+		for _, j := range ks {
+			sb.WriteString(t.cols[j].key(i))
+		}
+
+		g, ok := index[sb.String()]
+		if !ok {
+			g = len(groups)
+			index[sb.String()] = g
+			groups = append(groups, nil)
+		}
+
+		groups[g] = append(groups[g], i)
+	}
+
+	grp := newTable(h, len(groups))
+	for j := 0; j < len(ts); j++ {
+		grp.cols[j] = newVector(ts[j], len(groups))
+	}
+
+	r := make(Row, len(h))
+	for _, rows := range groups {
+		for k, j := range ks {
+			r[k] = t.cols[j].value(rows[0])
+		}
+
+		for k, a := range aggs {
+			if as[k] < 0 {
+				r[len(ks)+k] = len(rows)
+			} else {
+				r[len(ks)+k] = aggregate(&t.cols[as[k]], rows, a)
+			}
+		}
+
+		for j := 0; j < len(r); j++ {
+			if unify(grp.cols[j].typ, Parse(r[j])) == Inv {
+				return nil, ErrType
+			}
+		}
+
+		grp.Append(r)
+	}
+
+	return grp, nil
+}
+
+// aggType returns the type of an aggregation of the jth column.
+func aggType(a Aggregation, cols []vector, j int) (Type, error) {
+	switch a.Kind {
+	case AggCount, AggDistinct:
+		return Int, nil
+	case AggSum:
+		switch tp := cols[j].typ; tp {
+		case Int, Flt, Nil:
+			return tp, nil
+		default:
+			return Inv, ErrType
+		}
+	case AggMean:
+		switch cols[j].typ {
+		case Int, Flt, Nil:
+			return Flt, nil
+		default:
+			return Inv, ErrType
+		}
+	case AggMin, AggMax, AggFirst, AggLast:
+		return cols[j].typ, nil
+	case AggConcat:
+		return Str, nil
+	case AggFunc:
+		if a.Func == nil {
+			return Inv, ErrAggKind
+		}
+
+		return Nil, nil // Determined by the aggregated values
+	default:
+		return Inv, ErrAggKind
+	}
+}
+
+// aggregate returns the aggregation of the values of a vector in the
+// given rows.
+func aggregate(v *vector, rows []int, a Aggregation) interface{} {
+	switch a.Kind {
+	case AggCount:
+		var count int
+		for _, i := range rows {
+			if !v.nulls[i] {
+				count++
+			}
+		}
+
+		return count
+	case AggSum, AggMean:
+		var (
+			count int
+			sumI  int
+			sumF  float64
+		)
+
+		for _, i := range rows {
+			if v.nulls[i] {
+				continue
+			}
+
+			count++
+			if v.typ == Int {
+				sumI += v.ints[i]
+			} else {
+				sumF += v.flts[i]
+			}
+		}
+
+		switch {
+		case count == 0:
+			return Null
+		case a.Kind == AggMean && v.typ == Int:
+			return float64(sumI) / float64(count)
+		case a.Kind == AggMean:
+			return sumF / float64(count)
+		case v.typ == Int:
+			return sumI
+		default:
+			return sumF
+		}
+	case AggMin, AggMax:
+		k := -1
+		for _, i := range rows {
+			switch {
+			case v.nulls[i]:
+			case k < 0,
+				a.Kind == AggMin && v.compare(i, k) < 0,
+				a.Kind == AggMax && 0 < v.compare(i, k):
+				k = i
+			}
+		}
+
+		if k < 0 {
+			return Null
+		}
+
+		return v.value(k)
+	case AggFirst:
+		for _, i := range rows {
+			if !v.nulls[i] {
+				return v.value(i)
+			}
+		}
+
+		return Null
+	case AggLast:
+		for k := len(rows) - 1; 0 <= k; k-- {
+			if !v.nulls[rows[k]] {
+				return v.value(rows[k])
+			}
+		}
+
+		return Null
+	case AggDistinct:
+		seen := make(map[string]struct{})
+		for _, i := range rows {
+			if !v.nulls[i] {
+				seen[v.key(i)] = struct{}{}
+			}
+		}
+
+		return len(seen)
+	case AggConcat:
+		ss := make([]string, 0, len(rows))
+		for _, i := range rows {
+			if !v.nulls[i] {
+				ss = append(ss, v.str(i))
+			}
+		}
+
+		return strings.Join(ss, a.Sep)
+	case AggFunc:
+		c := make(Column, 0, len(rows))
+		for _, i := range rows {
+			c = append(c, v.value(i))
+		}
+
+		return a.Func(c)
+	default:
+		panic(errAggKind)
+	}
+}
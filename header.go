@@ -1,6 +1,8 @@
 package table
 
-import "strings"
+import (
+	"strings"
+)
 
 // Header is a list of column names.
 type Header []string
@@ -15,6 +17,23 @@ func (h Header) Copy() Header {
 	return append(make(Header, 0, len(h)), h...)
 }
 
+// Duplicates returns the names of columns that occur more than once
+// in a header in order of first appearance.
+func (h Header) Duplicates() []string {
+	var (
+		count = make(map[string]int, len(h))
+		dups  []string
+	)
+
+	for j := 0; j < len(h); j++ {
+		if count[h[j]]++; count[h[j]] == 2 {
+			dups = append(dups, h[j])
+		}
+	}
+
+	return dups
+}
+
 // Equal determines if two headers are equal.
 func (h Header) Equal(hdr Header) bool {
 	if len(h) != len(hdr) {
@@ -30,6 +49,51 @@ func (h Header) Equal(hdr Header) bool {
 	return true
 }
 
+// Index returns the index of the column named s. An error is returned
+// if no column or several columns are named s.
+func (h Header) Index(s string) (int, error) {
+	return h.indexFunc(func(name string) bool { return name == s })
+}
+
+// IndexFold returns the index of the column named s without regard to
+// case. An error is returned if no column or several columns are
+// named s.
+func (h Header) IndexFold(s string) (int, error) {
+	return h.indexFunc(func(name string) bool { return strings.EqualFold(name, s) })
+}
+
+// indexFunc returns the index of the only column name satisfying f.
+func (h Header) indexFunc(f func(name string) bool) (int, error) {
+	j := -1
+	for k := 0; k < len(h); k++ {
+		if f(h[k]) {
+			if 0 <= j {
+				return -1, ErrColDup
+			}
+
+			j = k
+		}
+	}
+
+	if j < 0 {
+		return -1, ErrColName
+	}
+
+	return j, nil
+}
+
+// index returns the index of the first column named s. If no column
+// is named s, -1 is returned.
+func (h Header) index(s string) int {
+	for j := 0; j < len(h); j++ {
+		if h[j] == s {
+			return j
+		}
+	}
+
+	return -1
+}
+
 // String ...
 func (h Header) String() string {
 	var sb strings.Builder
@@ -0,0 +1,101 @@
+package table
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"strings"
+)
+
+// HTMLOptions holds settings for rendering a table as html.
+type HTMLOptions struct {
+	// Class, if not empty, is the class attribute of the table
+	// element.
+	Class string
+
+	// Null is rendered in place of missing values.
+	Null string
+
+	// CellClass, if provided, returns additional classes for the
+	// (i,j)th cell holding a given value. It is called for every cell
+	// in the body, including missing values.
+	CellClass func(i, j int, v interface{}) string
+}
+
+// HTML returns a table as an html table element. A single set of
+// options may be passed. This panics if more than one set of options
+// is provided.
+func (t *Table) HTML(opts ...HTMLOptions) string {
+	var sb strings.Builder
+	t.WriteHTMLTo(&sb, opts...)
+	return sb.String()
+}
+
+// WriteHTMLTo writes a table to a writer as an html table element. The
+// header is written within thead and the rows within tbody. Each cell
+// has the class "col-" followed by the name of the column type, such as
+// "col-int". Integer and float cells also have the class "numeric" so
+// they may be right-aligned by a style sheet. All text is escaped. A
+// single set of options may be passed. This panics if more than one set
+// of options is provided.
+func (t *Table) WriteHTMLTo(w io.Writer, opts ...HTMLOptions) error {
+	var opt HTMLOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	var (
+		m, n    = t.Dims()
+		classes = make([]string, 0, n)
+		bw      = bufio.NewWriter(w)
+	)
+
+	for j := 0; j < n; j++ {
+		switch tp := t.cols[j].typ; tp {
+		case Int, Flt:
+			classes = append(classes, "col-"+tp.String()+" numeric")
+		default:
+			classes = append(classes, "col-"+tp.String())
+		}
+	}
+
+	if opt.Class != "" {
+		bw.WriteString(`<table class="` + html.EscapeString(opt.Class) + `">` + "\n")
+	} else {
+		bw.WriteString("<table>\n")
+	}
+
+	bw.WriteString("<thead>\n<tr>")
+	for j := 0; j < n; j++ {
+		bw.WriteString(`<th class="` + classes[j] + `">` + html.EscapeString(t.header[j]) + "</th>")
+	}
+
+	bw.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for i := 0; i < m; i++ {
+		bw.WriteString("<tr>")
+		for j := 0; j < n; j++ {
+			class := classes[j]
+			if opt.CellClass != nil {
+				if c := opt.CellClass(i, j, t.cols[j].value(i)); c != "" {
+					class += " " + c
+				}
+			}
+
+			s := opt.Null
+			if !t.cols[j].nulls[i] {
+				s = t.cols[j].str(i)
+			}
+
+			bw.WriteString(`<td class="` + html.EscapeString(class) + `">` + html.EscapeString(s) + "</td>")
+		}
+
+		bw.WriteString("</tr>\n")
+	}
+
+	bw.WriteString("</tbody>\n</table>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,246 @@
+package table
+
+import (
+	"strings"
+)
+
+const (
+	// InnerJoin retains pairs of rows having matching keys.
+	InnerJoin JoinKind = iota
+
+	// LeftJoin retains pairs of rows having matching keys and each
+	// left row having no match.
+	LeftJoin
+
+	// RightJoin retains pairs of rows having matching keys and each
+	// right row having no match.
+	RightJoin
+
+	// FullJoin retains pairs of rows having matching keys and each
+	// left or right row having no match.
+	FullJoin
+
+	// SemiJoin retains each left row having a match. Only the left
+	// columns are retained.
+	SemiJoin
+
+	// AntiJoin retains each left row having no match. Only the left
+	// columns are retained.
+	AntiJoin
+)
+
+// JoinKind determines which rows are retained when joining two tables
+// on key columns.
+type JoinKind byte
+
+// JoinOptions holds settings for joining two tables on key columns.
+type JoinOptions struct {
+	// RightOn, if provided, names the key columns of the right table.
+	// Otherwise, the key columns of both tables share the same names.
+	RightOn []string
+
+	// LeftSuffix and RightSuffix are appended to the names of left
+	// and right columns having the same name. If both are empty,
+	// "_left" and "_right" are used.
+	LeftSuffix, RightSuffix string
+}
+
+// JoinOn returns a new table joining two tables on one or more key
+// columns. The header consists of the left columns followed by the
+// right columns that are not keys. Each key column takes its values
+// from the left table unless the left row is missing. Missing keys
+// never match. A single set of options may be passed. This panics if
+// more than one set of options is provided.
+func JoinOn(left, right *Table, kind JoinKind, on []string, opts ...JoinOptions) (*Table, error) {
+	var opt JoinOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	if AntiJoin < kind {
+		return nil, ErrJoinKind
+	}
+
+	if opt.LeftSuffix == "" && opt.RightSuffix == "" {
+		opt.LeftSuffix, opt.RightSuffix = "_left", "_right"
+	}
+
+	rightOn := on
+	if 0 < len(opt.RightOn) {
+		rightOn = opt.RightOn
+	}
+
+	if len(on) == 0 || len(on) != len(rightOn) {
+		return nil, ErrDims
+	}
+
+	var (
+		m0, n0 = left.Dims()
+		m1, n1 = right.Dims()
+		lk     = make([]int, 0, len(on)) // Left key column indices
+		rk     = make([]int, 0, len(on)) // Right key column indices
+		isKey  = make([]int, n1)         // One more than the index into rk of each right key column; zero otherwise
+	)
+
+	for k := 0; k < len(on); k++ {
+		j0, err := left.colIndex(on[k])
+		if err != nil {
+			return nil, err
+		}
+
+		j1, err := right.colIndex(rightOn[k])
+		if err != nil {
+			return nil, err
+		}
+
+		if unify(left.cols[j0].typ, right.cols[j1].typ) == Inv {
+			return nil, ErrType
+		}
+
+		lk, rk = append(lk, j0), append(rk, j1)
+		isKey[j1] = k + 1
+	}
+
+	// Build the header and column types.
+	var (
+		h  = make(Header, 0, n0+n1)
+		ts = make(Types, 0, n0+n1)
+		rs = make([]int, 0, n1) // Right non-key column indices
+	)
+
+	h = append(h, left.header...)
+	ts = append(ts, left.ColTypes()...)
+	for k := 0; k < len(lk); k++ {
+		ts[lk[k]] = unify(ts[lk[k]], right.cols[rk[k]].typ)
+	}
+
+	if kind != SemiJoin && kind != AntiJoin {
+		for j := 0; j < n1; j++ {
+			if isKey[j] == 0 {
+				rs = append(rs, j)
+			}
+		}
+
+		for _, j1 := range rs {
+			name := right.header[j1]
+			if 0 <= left.header.index(name) {
+				for j := 0; j < n0; j++ {
+					if h[j] == name {
+						h[j] = name + opt.LeftSuffix
+					}
+				}
+
+				name += opt.RightSuffix
+			}
+
+			h = append(h, name)
+			ts = append(ts, right.cols[j1].typ)
+		}
+	}
+
+	t := newTable(h, 0)
+	for j := 0; j < len(ts); j++ {
+		t.cols[j] = newVector(ts[j], 0)
+	}
+
+	// Index the right rows by key.
+	index := make(map[string][]int)
+	for i := 0; i < m1; i++ {
+		if key, ok := joinKey(right, rk, i); ok {
+			index[key] = append(index[key], i)
+		}
+	}
+
+	var (
+		matched = make([]bool, m1)
+		r       = make(Row, len(h))
+	)
+
+	for i := 0; i < m0; i++ {
+		var matches []int
+		if key, ok := joinKey(left, lk, i); ok {
+			matches = index[key]
+		}
+
+		switch kind {
+		case SemiJoin:
+			if 0 < len(matches) {
+				t.Append(left.Row(i))
+			}
+
+			continue
+		case AntiJoin:
+			if len(matches) == 0 {
+				t.Append(left.Row(i))
+			}
+
+			continue
+		}
+
+		for j := 0; j < n0; j++ {
+			r[j] = left.cols[j].value(i)
+		}
+
+		for _, i1 := range matches {
+			matched[i1] = true
+			for k, j1 := range rs {
+				r[n0+k] = right.cols[j1].value(i1)
+			}
+
+			t.Append(r)
+		}
+
+		if len(matches) == 0 && (kind == LeftJoin || kind == FullJoin) {
+			for k := range rs {
+				r[n0+k] = Null
+			}
+
+			t.Append(r)
+		}
+	}
+
+	if kind == RightJoin || kind == FullJoin {
+		for i1 := 0; i1 < m1; i1++ {
+			if matched[i1] {
+				continue
+			}
+
+			for j := 0; j < n0; j++ {
+				r[j] = Null
+			}
+
+			for k := 0; k < len(lk); k++ {
+				r[lk[k]] = right.cols[rk[k]].value(i1)
+			}
+
+			for k, j1 := range rs {
+				r[n0+k] = right.cols[j1].value(i1)
+			}
+
+			t.Append(r)
+		}
+	}
+
+	return t, nil
+}
+
+// joinKey returns a string uniquely identifying the values of the
+// given columns in the ith row. If any value is missing or not a
+// number, false is returned.
+func joinKey(t *Table, cols []int, i int) (string, bool) {
+	var sb strings.Builder
+	for _, j := range cols {
+		v := &t.cols[j]
+		if v.nulls[i] || (v.typ == Flt && v.flts[i] != v.flts[i]) {
+			return "", false
+		}
+
+		sb.WriteString(v.key(i))
+	}
+
+	return sb.String(), true
+}
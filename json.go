@@ -0,0 +1,385 @@
+package table
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// Floats that are not finite have no json encoding as numbers. Wherever
+// a table is written as json, they are encoded as the following strings,
+// and wherever json is read into a table, these strings are read as
+// floats if the column holds numbers.
+const (
+	jsonNaN    = "NaN"
+	jsonPosInf = "Infinity"
+	jsonNegInf = "-Infinity"
+)
+
+// JSONOptions holds settings for reading and writing json-encoded
+// records.
+type JSONOptions struct {
+	// Header, if provided, selects and orders the columns. Keys not in
+	// the header are ignored. Otherwise, columns are ordered by the
+	// first appearance of each key.
+	Header Header
+
+	// TimeFmts are time formats tried after RFC 3339 when deciding
+	// whether a column of strings holds time stamps.
+	TimeFmts []string
+}
+
+// FromJSONRecords returns a new table with data parsed from a list of
+// json-encoded objects, each holding one row.
+//
+//	[{"col":value, ...}, ...]
+//
+// Column types are inferred from the values. Numbers are integers if
+// every number in the column is an integer and floats otherwise.
+// Strings are time stamps if every string in the column parses as one.
+// The strings "NaN", "Infinity", and "-Infinity" are read as floats
+// unless the column holds other strings, times, or booleans. Missing
+// keys and null values are read as missing values. An error's Value is
+// the raw json of an offending record or the decoded offending value.
+// A single set of options may be passed. This panics if more than one
+// set of options is provided.
+func FromJSONRecords(s string, opts ...JSONOptions) (*Table, error) {
+	var opt JSONOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	if !gjson.Valid(s) {
+		return nil, ErrJSON
+	}
+
+	records := gjson.Parse(s)
+	if !records.IsArray() {
+		return nil, ErrJSON
+	}
+
+	var (
+		rs = records.Array()
+		h  = append(make(Header, 0, len(opt.Header)), opt.Header...)
+		ks = make(map[string]int) // Index of each column by name
+	)
+
+	for j := 0; j < len(h); j++ {
+		ks[h[j]] = j
+	}
+
+	for i := 0; i < len(rs); i++ {
+		if !rs[i].IsObject() {
+			return nil, &Error{Err: ErrJSON, Row: i, Col: -1, Value: rs[i].Raw}
+		}
+
+		if len(opt.Header) == 0 {
+			rs[i].ForEach(func(k, _ gjson.Result) bool {
+				if _, ok := ks[k.String()]; !ok {
+					ks[k.String()] = len(h)
+					h = append(h, k.String())
+				}
+
+				return true
+			})
+		}
+	}
+
+	// Gather the values of each column.
+	values := make([][]gjson.Result, len(h))
+	for j := 0; j < len(h); j++ {
+		values[j] = make([]gjson.Result, len(rs))
+	}
+
+	for i := 0; i < len(rs); i++ {
+		rs[i].ForEach(func(k, v gjson.Result) bool {
+			if j, ok := ks[k.String()]; ok {
+				values[j][i] = v
+			}
+
+			return true
+		})
+	}
+
+	t := newTable(h, len(rs))
+	for j := 0; j < len(h); j++ {
+		v, err := jsonVector(values[j], opt.TimeFmts)
+		if err != nil {
+			if e, ok := err.(*Error); ok {
+				e.Col, e.Name = j, h[j]
+			}
+
+			return nil, err
+		}
+
+		t.cols[j] = v
+	}
+
+	return t, nil
+}
+
+// jsonVector returns a new vector holding a list of json values. The
+// vector's type is inferred from the values.
+func jsonVector(values []gjson.Result, timeFmts []string) (vector, error) {
+	var (
+		tp        = Nil
+		nonFinite = -1 // Index of the first float that is not finite
+	)
+
+	for i := 0; i < len(values); i++ {
+		var next Type
+		switch values[i].Type {
+		case gjson.Null:
+			continue
+		case gjson.Number:
+			next = Int
+			if !isJSONInt(values[i].Raw) {
+				next = Flt
+			}
+		case gjson.True, gjson.False:
+			next = Bool
+		case gjson.String:
+			if _, ok := jsonFloat(values[i].Str); ok {
+				if nonFinite < 0 {
+					nonFinite = i
+				}
+
+				continue // Decided once the column's type is known
+			}
+
+			next = Time
+		default:
+			next = Inv
+		}
+
+		switch {
+		case next == Inv:
+			return vector{}, &Error{Err: ErrType, Row: i, Col: -1, Type: tp, Value: jsonValue(values[i])}
+		case tp == next, tp == Flt && next == Int:
+		case tp == Nil, tp == Int && next == Flt:
+			tp = next
+		default:
+			return vector{}, &Error{Err: ErrType, Row: i, Col: -1, Type: tp, Value: jsonValue(values[i])}
+		}
+	}
+
+	if 0 <= nonFinite {
+		switch tp {
+		case Nil, Int:
+			tp = Flt
+		case Bool:
+			return vector{}, &Error{Err: ErrType, Row: nonFinite, Col: -1, Type: tp, Value: jsonValue(values[nonFinite])}
+		default:
+		}
+	}
+
+	if tp == Time {
+		for i := 0; i < len(values) && tp == Time; i++ {
+			if values[i].Type == gjson.String {
+				if _, err := parseJSONTime(values[i].Str, timeFmts); err != nil {
+					tp = Str
+				}
+			}
+		}
+	}
+
+	v := newVector(tp, len(values))
+	for i := 0; i < len(values); i++ {
+		if values[i].Type == gjson.Null {
+			v.append(Null)
+			continue
+		}
+
+		switch tp {
+		case Int:
+			v.append(int(values[i].Int()))
+		case Flt:
+			if values[i].Type == gjson.String {
+				f, _ := jsonFloat(values[i].Str)
+				v.append(f)
+			} else {
+				v.append(values[i].Float())
+			}
+		case Bool:
+			v.append(values[i].Bool())
+		case Time:
+			ft, _ := parseJSONTime(values[i].Str, timeFmts)
+			v.append(ft)
+		case Str:
+			v.append(values[i].Str)
+		default:
+		}
+	}
+
+	return v, nil
+}
+
+// isJSONInt determines if a json-encoded number is an integer that may
+// be held in an int.
+func isJSONInt(raw string) bool {
+	_, err := strconv.ParseInt(raw, 10, strconv.IntSize)
+	return err == nil
+}
+
+// jsonValue returns a json value decoded as it is held in a table or,
+// if it is an object or array, as encoding/json decodes it.
+func jsonValue(v gjson.Result) interface{} {
+	switch v.Type {
+	case gjson.Null:
+		return Null
+	case gjson.Number:
+		if isJSONInt(v.Raw) {
+			return int(v.Int())
+		}
+
+		return v.Float()
+	case gjson.True, gjson.False:
+		return v.Bool()
+	case gjson.String:
+		return v.Str
+	default:
+		return v.Value()
+	}
+}
+
+// jsonFloat returns the float encoded by a string used for floats that
+// are not finite.
+func jsonFloat(s string) (float64, bool) {
+	switch s {
+	case jsonNaN:
+		return math.NaN(), true
+	case jsonPosInf:
+		return math.Inf(1), true
+	case jsonNegInf:
+		return math.Inf(-1), true
+	default:
+		return 0, false
+	}
+}
+
+// parseJSONTime returns a time stamp parsed from a string formatted as
+// RFC 3339 or any of the given formats.
+func parseJSONTime(s string, timeFmts []string) (FTime, error) {
+	if tm, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return NewFTime(tm), nil
+	}
+
+	for _, f := range timeFmts {
+		if tm, err := time.Parse(f, s); err == nil {
+			return NewFTime(tm, f), nil
+		}
+	}
+
+	return FTime{}, ErrTimeFmt
+}
+
+// JSONRecords returns a table as a json-encoded list of objects, each
+// holding one row. Missing values are encoded as null.
+func (t *Table) JSONRecords() string {
+	var sb strings.Builder
+	t.WriteJSONRecordsTo(&sb)
+	return sb.String()
+}
+
+// WriteJSONRecordsTo writes a table to a writer as a json-encoded list
+// of objects, each holding one row. Missing values are encoded as null
+// and floats that are not finite as the strings "NaN", "Infinity", and
+// "-Infinity". Time stamps are encoded as strings in their own format.
+func (t *Table) WriteJSONRecordsTo(w io.Writer) error {
+	var (
+		m, _ = t.Dims()
+		bw   = bufio.NewWriter(w)
+		keys = jsonKeys(t.header)
+		b    []byte
+	)
+
+	bw.WriteByte('[')
+	for i := 0; i < m; i++ {
+		if 0 < i {
+			bw.WriteByte(',')
+		}
+
+		b = appendJSONObject(b[:0], keys, t.Row(i))
+		bw.Write(b)
+	}
+
+	bw.WriteByte(']')
+	return bw.Flush()
+}
+
+// jsonKeys returns each column name encoded as a json object key.
+func jsonKeys(h Header) [][]byte {
+	keys := make([][]byte, 0, len(h))
+	for j := 0; j < len(h); j++ {
+		keys = append(keys, append(appendJSONString(nil, h[j]), ':'))
+	}
+
+	return keys
+}
+
+// appendJSONObject appends a row, encoded as a json object, to a list
+// of bytes. Each key is assumed to be encoded by jsonKeys.
+func appendJSONObject(b []byte, keys [][]byte, r Row) []byte {
+	b = append(b, '{')
+	for j := 0; j < len(r); j++ {
+		if 0 < j {
+			b = append(b, ',')
+		}
+
+		b = append(b, keys[j]...)
+		b = appendJSONValue(b, r[j])
+	}
+
+	return append(b, '}')
+}
+
+// appendJSONValue appends a value, encoded as json, to a list of
+// bytes. Missing values are encoded as null and floats that are not
+// finite as strings. Time stamps are encoded as strings in their own
+// format.
+func appendJSONValue(b []byte, x interface{}) []byte {
+	switch Parse(x) {
+	case Int, Bool:
+		return append(b, formatValue(x)...)
+	case Flt:
+		return appendJSONFloat(b, x.(float64))
+	case Time, Str:
+		return appendJSONString(b, formatValue(x))
+	case Nil:
+		return append(b, "null"...)
+	default:
+		panic(errType)
+	}
+}
+
+// appendJSONFloat appends a float, encoded as json, to a list of bytes.
+// Floats that are not finite are encoded as strings.
+func appendJSONFloat(b []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return appendJSONString(b, jsonNaN)
+	case math.IsInf(f, 1):
+		return appendJSONString(b, jsonPosInf)
+	case math.IsInf(f, -1):
+		return appendJSONString(b, jsonNegInf)
+	default:
+		return append(b, formatValue(f)...)
+	}
+}
+
+// appendJSONString appends a string, quoted and escaped as json, to a
+// list of bytes. Strings are escaped as encoding/json escapes them.
+func appendJSONString(b []byte, s string) []byte {
+	q, _ := json.Marshal(s) // Strings always marshal
+	return append(b, q...)
+}
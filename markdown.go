@@ -0,0 +1,115 @@
+package table
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// markdownEscaper escapes characters having special meaning within a
+// markdown table cell.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// MarkdownOptions holds settings for writing markdown tables.
+type MarkdownOptions struct {
+	// Formatters, if provided, converts the values of each column to
+	// strings. Columns without a formatter are written as the values'
+	// default string representation. Missing values are not passed to
+	// formatters.
+	Formatters []Formatter
+}
+
+// Markdown returns a table as a GitHub-flavored markdown table. Integer
+// and float columns are right-aligned. Missing values are rendered as
+// empty cells. A single set of options may be passed. This panics if
+// more than one set of options is provided.
+func (t *Table) Markdown(opts ...MarkdownOptions) string {
+	var sb strings.Builder
+	t.WriteMarkdownTo(&sb, opts...)
+	return sb.String()
+}
+
+// WriteMarkdownTo writes a table to a writer as a GitHub-flavored
+// markdown table. A single set of options may be passed. This panics if
+// more than one set of options is provided.
+func (t *Table) WriteMarkdownTo(w io.Writer, opts ...MarkdownOptions) error {
+	var opt MarkdownOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	var (
+		m, n  = t.Dims()
+		cells = make([][]string, 0, m+1)
+		ws    = make([]int, n) // Column widths
+		right = make([]bool, n)
+	)
+
+	if n == 0 {
+		return nil
+	}
+
+	for j := 0; j < n; j++ {
+		right[j] = t.cols[j].typ == Int || t.cols[j].typ == Flt
+	}
+
+	cells = append(cells, make([]string, 0, n))
+	for j := 0; j < n; j++ {
+		cells[0] = append(cells[0], markdownEscaper.Replace(t.header[j]))
+	}
+
+	for i := 0; i < m; i++ {
+		r := make([]string, 0, n)
+		for j := 0; j < n; j++ {
+			if !t.cols[j].nulls[i] && j < len(opt.Formatters) && opt.Formatters[j] != nil {
+				r = append(r, markdownEscaper.Replace(opt.Formatters[j](t.cols[j].value(i))))
+			} else {
+				r = append(r, markdownEscaper.Replace(t.cols[j].str(i)))
+			}
+		}
+
+		cells = append(cells, r)
+	}
+
+	for j := 0; j < n; j++ {
+		ws[j] = 3 // Shortest delimiter
+		for i := 0; i < len(cells); i++ {
+			if w := width(cells[i][j]); ws[j] < w {
+				ws[j] = w
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for i := 0; i < len(cells); i++ {
+		bw.WriteByte('|')
+		for j := 0; j < n; j++ {
+			pad := padding(cells[i][j], ws[j])
+			if right[j] {
+				bw.WriteString(" " + pad + cells[i][j] + " |")
+			} else {
+				bw.WriteString(" " + cells[i][j] + pad + " |")
+			}
+		}
+
+		bw.WriteByte('\n')
+		if i == 0 {
+			bw.WriteByte('|')
+			for j := 0; j < n; j++ {
+				if right[j] {
+					bw.WriteString(" " + strings.Repeat("-", ws[j]-1) + ": |")
+				} else {
+					bw.WriteString(" " + strings.Repeat("-", ws[j]) + " |")
+				}
+			}
+
+			bw.WriteByte('\n')
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,308 @@
+package table
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// NDJSONReader reads newline-delimited json, one object per line, into
+// tables. Lines are read as they arrive, so arbitrarily long streams
+// may be read in batches.
+type NDJSONReader struct {
+	br  *bufio.Reader
+	opt JSONOptions
+	eof bool
+}
+
+// NDJSONWriter writes rows as newline-delimited json, one object per
+// line.
+type NDJSONWriter struct {
+	bw   *bufio.Writer
+	keys [][]byte
+	b    []byte
+}
+
+// NewNDJSONReader returns a new reader of newline-delimited json. A
+// single set of options may be passed. This panics if more than one set
+// of options is provided.
+func NewNDJSONReader(r io.Reader, opts ...JSONOptions) *NDJSONReader {
+	var opt JSONOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	return &NDJSONReader{br: bufio.NewReader(r), opt: opt}
+}
+
+// FromNDJSONReader returns a new table with data read from a reader of
+// newline-delimited json. Types are inferred as they are by
+// FromJSONRecords. A single set of options may be passed. This panics
+// if more than one set of options is provided.
+func FromNDJSONReader(r io.Reader, opts ...JSONOptions) (*Table, error) {
+	var (
+		nr = NewNDJSONReader(r, opts...)
+		t  = New(nr.opt.Header)
+	)
+
+	if _, err := nr.ReadInto(t, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ReadInto appends up to max rows to a table, reading one line per row.
+// If max is not positive, every remaining line is read. The number of
+// rows appended is returned. Once every line has been read, io.EOF is
+// returned.
+//
+// Each key is matched to the first column of the same name. If a header
+// option was given, the table's columns are taken from it when the
+// table has none and keys not in the table are ignored. Otherwise, a
+// new key appends a column of missing values before its value is set.
+// Missing keys are read as missing values. An integer column is
+// converted to floats when a non-integer number is read into it and a
+// time column is converted to strings when a string that is not a time
+// stamp is read into it. The strings "NaN", "Infinity", and "-Infinity"
+// are read as floats into columns of numbers or missing values. Rows
+// read before an error remain appended; the line causing it leaves the
+// table unchanged. An error's Value is the decoded offending value.
+func (nr *NDJSONReader) ReadInto(t *Table, max int) (int, error) {
+	if nr.eof {
+		return 0, io.EOF
+	}
+
+	if 0 < len(nr.opt.Header) && len(t.header) == 0 {
+		for _, name := range nr.opt.Header {
+			t.AppendCol(name, nil)
+		}
+	}
+
+	ks := make(map[string]int) // Index of each column by name
+	for j := len(t.header) - 1; 0 <= j; j-- {
+		ks[t.header[j]] = j
+	}
+
+	var count int
+	for max <= 0 || count < max {
+		line, err := nr.br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return count, err
+		}
+
+		if err == io.EOF {
+			nr.eof = true
+		}
+
+		if line = bytes.TrimSpace(line); 0 < len(line) {
+			if err := nr.appendLine(t, ks, line); err != nil {
+				return count, err
+			}
+
+			count++
+		}
+
+		if nr.eof {
+			if count == 0 {
+				return 0, io.EOF
+			}
+
+			break
+		}
+	}
+
+	return count, nil
+}
+
+// appendLine appends a row read from a json-encoded object to a table.
+// The whole line is checked before the table is changed, so a line that
+// does not fit adds no columns and widens none.
+func (nr *NDJSONReader) appendLine(t *Table, ks map[string]int, line []byte) error {
+	m, n := t.Dims()
+	if !gjson.ValidBytes(line) {
+		return &Error{Err: ErrJSON, Row: m, Col: -1, Value: string(line)}
+	}
+
+	obj := gjson.ParseBytes(line)
+	if !obj.IsObject() {
+		return &Error{Err: ErrJSON, Row: m, Col: -1, Value: obj.Raw}
+	}
+
+	var (
+		ts    = t.ColTypes()         // Column types once the row is appended
+		names []string               // Names of new columns
+		added = make(map[string]int) // Index of each new column by name
+		r     Row
+		err   error
+	)
+
+	obj.ForEach(func(k, v gjson.Result) bool {
+		j, ok := ks[k.String()]
+		if !ok {
+			if 0 < len(nr.opt.Header) {
+				return true
+			}
+
+			if j, ok = added[k.String()]; !ok {
+				j = n + len(names)
+				added[k.String()] = j
+				names = append(names, k.String())
+				ts = append(ts, Nil)
+			}
+		}
+
+		for len(r) <= j {
+			r = append(r, Null)
+		}
+
+		var (
+			x  interface{}
+			tp Type
+		)
+
+		if x, tp, err = nr.value(ts[j], v); err != nil {
+			e := newError(err, t, m, j, jsonValue(v))
+			if n <= j {
+				e.Name, e.Type = k.String(), ts[j]
+			}
+
+			err = e
+			return false
+		}
+
+		r[j], ts[j] = x, tp
+		return true
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		j := len(t.header)
+		ks[name] = j
+		t.header = append(t.header, name)
+		t.cols = append(t.cols, newVector(Nil, m))
+		for i := 0; i < m; i++ {
+			t.cols[j].append(Null)
+		}
+	}
+
+	for j := 0; j < n; j++ {
+		if t.cols[j].typ != Nil && t.cols[j].typ != ts[j] {
+			t.cols[j].widen(ts[j])
+		}
+	}
+
+	for len(r) < len(t.header) {
+		r = append(r, Null)
+	}
+
+	return t.TryAppend(r)
+}
+
+// value returns a json value converted to fit a column of a given type
+// together with the type of the column once the value is stored in it.
+// An integer column is widened to floats by a non-integer number and a
+// time column is widened to strings by a string that is not a time
+// stamp. An error is returned if the value does not fit the column.
+func (nr *NDJSONReader) value(tp Type, v gjson.Result) (interface{}, Type, error) {
+	var x interface{}
+	switch v.Type {
+	case gjson.Null:
+		return Null, tp, nil
+	case gjson.Number:
+		switch {
+		case tp == Int && !isJSONInt(v.Raw):
+			return v.Float(), Flt, nil
+		case tp == Flt || !isJSONInt(v.Raw):
+			x = v.Float()
+		default:
+			x = int(v.Int())
+		}
+	case gjson.True, gjson.False:
+		x = v.Bool()
+	case gjson.String:
+		if f, ok := jsonFloat(v.Str); ok && (tp == Nil || tp == Int || tp == Flt) {
+			return f, Flt, nil
+		}
+
+		x = v.Str
+		if tp == Nil || tp == Time {
+			if ft, err := parseJSONTime(v.Str, nr.opt.TimeFmts); err == nil {
+				x = ft
+			} else if tp == Time {
+				return v.Str, Str, nil
+			}
+		}
+	default:
+		return nil, tp, ErrType
+	}
+
+	next := unify(tp, Parse(x))
+	if next == Inv {
+		return nil, tp, ErrType
+	}
+
+	return x, next, nil
+}
+
+// NewNDJSONWriter returns a new writer of rows having a given header.
+func NewNDJSONWriter(w io.Writer, h Header) *NDJSONWriter {
+	return &NDJSONWriter{bw: bufio.NewWriter(w), keys: jsonKeys(h)}
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (nw *NDJSONWriter) Flush() error {
+	return nw.bw.Flush()
+}
+
+// Write a row as a json-encoded object followed by a new line. Values
+// are encoded as they are by WriteJSONRecordsTo.
+func (nw *NDJSONWriter) Write(r Row) error {
+	if len(r) != len(nw.keys) {
+		return &Error{Err: ErrDims, Row: -1, Col: -1, Value: r}
+	}
+
+	for j := 0; j < len(r); j++ {
+		if Parse(r[j]) == Inv {
+			return &Error{Err: ErrType, Row: -1, Col: j, Type: Inv, Value: r[j]}
+		}
+	}
+
+	nw.b = append(appendJSONObject(nw.b[:0], nw.keys, r), '\n')
+	_, err := nw.bw.Write(nw.b)
+	return err
+}
+
+// NDJSON returns a table as newline-delimited json, one object per row.
+func (t *Table) NDJSON() string {
+	var sb strings.Builder
+	t.WriteNDJSONTo(&sb)
+	return sb.String()
+}
+
+// WriteNDJSONTo writes a table to a writer as newline-delimited json,
+// one object per row.
+func (t *Table) WriteNDJSONTo(w io.Writer) error {
+	var (
+		m, _ = t.Dims()
+		nw   = NewNDJSONWriter(w, t.header)
+	)
+
+	for i := 0; i < m; i++ {
+		if err := nw.Write(t.Row(i)); err != nil {
+			return err
+		}
+	}
+
+	return nw.Flush()
+}
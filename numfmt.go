@@ -0,0 +1,238 @@
+package table
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// NumDefault displays the shortest representation of a number.
+	// Floats having integer values end in ".0".
+	NumDefault NumKind = iota
+
+	// NumFixed displays a number with Prec digits after the decimal
+	// point.
+	NumFixed
+
+	// NumSig displays a number rounded to Prec significant digits.
+	NumSig
+
+	// NumPercent displays a number multiplied by 100 with Prec digits
+	// after the decimal point followed by "%".
+	NumPercent
+
+	// NumSci displays a number in scientific notation with Prec digits
+	// after the decimal point.
+	NumSci
+
+	// NumSI displays a number scaled by an SI prefix, such as k for
+	// thousands or µ for millionths, with Prec digits after the
+	// decimal point.
+	NumSI
+)
+
+// siPrefixes are the SI prefixes from 10^-24 to 10^24 in steps of 10^3.
+var siPrefixes = [...]string{"y", "z", "a", "f", "p", "n", "µ", "m", "", "k", "M", "G", "T", "P", "E", "Z", "Y"}
+
+type (
+	// NumKind determines how a number is displayed.
+	NumKind byte
+
+	// NumFormat defines how integers and floats are displayed. Its
+	// Formatter may be used wherever a Formatter is accepted.
+	NumFormat struct {
+		// Kind determines the notation.
+		Kind NumKind
+
+		// Prec is the number of digits after the decimal point, or the
+		// number of significant digits if Kind is NumSig.
+		Prec int
+
+		// Group separates each group of three integer digits by Sep.
+		Group bool
+
+		// Sep separates groups of digits. If empty, "," is used. If
+		// ".", the decimal point is marked by "," instead.
+		Sep string
+
+		// Prefix and Suffix surround each number, such as a currency
+		// symbol or a unit.
+		Prefix, Suffix string
+	}
+)
+
+// Formatter returns a formatter displaying integers and floats by a
+// number format.
+func (nf NumFormat) Formatter() Formatter {
+	return nf.Format
+}
+
+// Format returns an integer or float formatted by a number format.
+// Other values are returned as their default string representation.
+func (nf NumFormat) Format(v interface{}) string {
+	var (
+		x     float64
+		isInt bool
+	)
+
+	switch v := v.(type) {
+	case int:
+		x, isInt = float64(v), true
+	case float64:
+		x = v
+	default:
+		return formatValue(v)
+	}
+
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+
+	prec := nf.Prec
+	if prec < 0 {
+		prec = 0
+	}
+
+	var s string
+	switch nf.Kind {
+	case NumDefault:
+		if isInt {
+			s = strconv.Itoa(v.(int))
+		} else {
+			s = formatFloat(x)
+		}
+	case NumFixed:
+		s = strconv.FormatFloat(x, 'f', prec, 64)
+	case NumSig:
+		s = formatSig(x, prec)
+	case NumPercent:
+		s = strconv.FormatFloat(100*x, 'f', prec, 64) + "%"
+	case NumSci:
+		s = strconv.FormatFloat(x, 'e', prec, 64)
+	case NumSI:
+		s = formatSI(x, prec)
+	default:
+		panic(errType)
+	}
+
+	if nf.Sep == "." {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+
+	if nf.Group && nf.Kind != NumSci {
+		sep := nf.Sep
+		if sep == "" {
+			sep = ","
+		}
+
+		s = group(s, sep)
+	}
+
+	return nf.Prefix + s + nf.Suffix
+}
+
+// formatSig returns a number rounded to a number of significant digits
+// without an exponent. If the number of digits is not positive, the
+// shortest representation is returned.
+func formatSig(x float64, digits int) string {
+	if digits <= 0 {
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+
+	if x == 0 {
+		return strconv.FormatFloat(0, 'f', digits-1, 64)
+	}
+
+	// Round using the exponent found by the 'e' format, which accounts
+	// for rounding up to the next power of ten.
+	s := strconv.FormatFloat(x, 'e', digits-1, 64)
+	k := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[k+1:])
+	if decimals := digits - 1 - exp; 0 <= decimals {
+		return strconv.FormatFloat(x, 'f', decimals, 64)
+	}
+
+	y, _ := strconv.ParseFloat(s, 64)
+	return strconv.FormatFloat(y, 'f', 0, 64)
+}
+
+// formatSI returns a number scaled by an SI prefix with a number of
+// digits after the decimal point.
+func formatSI(x float64, prec int) string {
+	k := 8 // Index of the empty prefix
+	if x != 0 {
+		k += int(math.Floor(math.Log10(math.Abs(x)) / 3))
+	}
+
+	switch {
+	case k < 0:
+		k = 0
+	case len(siPrefixes) <= k:
+		k = len(siPrefixes) - 1
+	}
+
+	y := x / math.Pow(1000, float64(k-8))
+	s := strconv.FormatFloat(y, 'f', prec, 64)
+	if r, _ := strconv.ParseFloat(s, 64); 1000 <= math.Abs(r) && k+1 < len(siPrefixes) {
+		// Rounding reached the next prefix
+		k++
+		s = strconv.FormatFloat(x/math.Pow(1000, float64(k-8)), 'f', prec, 64)
+	}
+
+	return s + siPrefixes[k]
+}
+
+// group returns a formatted number with each group of three integer
+// digits separated by a separator.
+func group(s, sep string) string {
+	start := 0
+	if 0 < len(s) && (s[0] == '-' || s[0] == '+') {
+		start = 1
+	}
+
+	end := start
+	for end < len(s) && '0' <= s[end] && s[end] <= '9' {
+		end++
+	}
+
+	if end-start <= 3 {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s) + (end-start-1)/3*len(sep))
+	sb.WriteString(s[:start])
+	for i := start; i < end; i++ {
+		if start < i && (end-i)%3 == 0 {
+			sb.WriteString(sep)
+		}
+
+		sb.WriteByte(s[i])
+	}
+
+	sb.WriteString(s[end:])
+	return sb.String()
+}
+
+// pointTail returns the part of a formatted number beginning at its
+// decimal point, marked by a given string, or, if it has none,
+// following its last digit.
+func pointTail(s, point string) string {
+	if k := strings.Index(s, point); 0 <= k {
+		return s[k:]
+	}
+
+	k := len(s)
+	for 0 < k && (s[k-1] < '0' || '9' < s[k-1]) {
+		k--
+	}
+
+	return s[k:]
+}
+
+// hasDigit determines if a string holds a decimal digit. Formatted
+// numbers without digits, such as "NaN" and "+Inf", have no tail.
+func hasDigit(s string) bool {
+	return 0 <= strings.IndexAny(s, "0123456789")
+}
@@ -0,0 +1,294 @@
+package table
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// formatWriter writes a formatted table. The newline ending each line
+// is held back until more is written so the last may be omitted.
+type formatWriter struct {
+	bw *bufio.Writer
+	nl bool // A newline is held back
+}
+
+// WriteString writes a string.
+func (fw *formatWriter) WriteString(s string) {
+	if s == "" {
+		return
+	}
+
+	if fw.nl {
+		fw.bw.WriteByte('\n')
+		fw.nl = false
+	}
+
+	if s[len(s)-1] == '\n' {
+		s, fw.nl = s[:len(s)-1], true
+	}
+
+	fw.bw.WriteString(s)
+}
+
+// WriteFormatTo writes a formatted table to a writer given format rules,
+// one row at a time. Columns are sized by the display width of their
+// values, so wide characters, combining marks, and ANSI escape sequences
+// do not misalign them. Values holding newlines span several lines, and
+// the decimal points of right-aligned numbers line up. A single set of
+// options may be passed to align, limit, wrap, or format columns, or to
+// size them from a sample of rows. This panics if more than one set of
+// options is provided.
+func (t *Table) WriteFormatTo(w io.Writer, fmt Format, opts ...FormatOptions) error {
+	var opt FormatOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		opt = opts[0]
+	default:
+		panic(errVarCount)
+	}
+
+	if opt.Ellipsis == "" {
+		opt.Ellipsis = "…"
+	}
+
+	var (
+		m, n   = t.Dims()
+		sample = m
+		cfs    = make([]ColFormat, 0, n) // Column settings
+		ws     = make([]int, n)          // Column widths
+		tws    = make([]int, n)          // Widest tail of each right-aligned number column
+		h      = make([][]string, 0, n)  // Lines of each header
+	)
+
+	if 0 < opt.SampleRows && opt.SampleRows < m {
+		sample = opt.SampleRows
+	}
+
+	for j := 0; j < n; j++ {
+		cf, ok := opt.Cols[t.header[j]]
+		if !ok {
+			cf = opt.Default
+		}
+
+		if cf.Point == "" {
+			cf.Point = "."
+		}
+
+		if cf.Align == AlignDefault {
+			switch t.cols[j].typ {
+			case Flt, Int:
+				cf.Align = AlignRight
+			case Bool, Time, Str, Nil:
+				cf.Align = AlignLeft
+			default:
+				panic(errType)
+			}
+		}
+
+		cfs = append(cfs, cf)
+		h = append(h, lines(t.header[j]))
+	}
+
+	// Measure the header and sampled rows.
+	cells := make([][][]string, 0, sample) // Lines of each sampled value
+	for i := 0; i < sample; i++ {
+		cells = append(cells, t.formatRow(i, fmt, cfs))
+	}
+
+	for j := 0; j < n; j++ {
+		if cfs[j].Align != AlignRight || (t.cols[j].typ != Int && t.cols[j].typ != Flt) {
+			tws[j] = -1
+			continue
+		}
+
+		for i := 0; i < sample; i++ {
+			if !t.cols[j].nulls[i] && len(cells[i][j]) == 1 && hasDigit(cells[i][j][0]) {
+				if w := width(pointTail(cells[i][j][0], cfs[j].Point)); tws[j] < w {
+					tws[j] = w
+				}
+			}
+		}
+	}
+
+	for i := 0; i < sample; i++ {
+		t.alignPoints(i, cells[i], cfs, tws)
+	}
+
+	for k := 0; k <= len(cells); k++ {
+		r := h
+		if k < len(cells) {
+			r = cells[k]
+		}
+
+		for j := 0; j < n; j++ {
+			for _, line := range r[j] {
+				if w := width(line); ws[j] < w {
+					ws[j] = w
+				}
+			}
+		}
+	}
+
+	for j := 0; j < n; j++ {
+		if 0 < cfs[j].MaxWidth && cfs[j].MaxWidth < ws[j] {
+			ws[j] = cfs[j].MaxWidth
+		}
+
+		if ws[j] < cfs[j].MinWidth {
+			ws[j] = cfs[j].MinWidth
+		}
+	}
+
+	// Write each row.
+	fw := formatWriter{bw: bufio.NewWriter(w)}
+	if !opt.NoLeadingNewline {
+		fw.bw.WriteByte('\n')
+	}
+
+	writeRule(&fw, ws, fmt.UpperHoriz, fmt.UpperLeftHorizDelim, fmt.UpperMidHorizDelim, fmt.UpperRightHorizDelim)
+	writeCells(&fw, fitCells(h, ws, cfs, opt.Ellipsis), ws, cfs, fmt.HeaderLeftDelim, fmt.HeaderMidDelim, fmt.HeaderRightDelim)
+	writeRule(&fw, ws, fmt.MiddleHoriz, fmt.MiddleLeftHorizDelim, fmt.MiddleMidHorizDelim, fmt.MiddleRightHorizDelim)
+	for i := 0; i < m && 0 < n; i++ {
+		if 0 < i {
+			writeRule(&fw, ws, fmt.SepHoriz, fmt.SepLeftHorizDelim, fmt.SepMidHorizDelim, fmt.SepRightHorizDelim)
+		}
+
+		var r [][]string
+		if i < sample {
+			r, cells[i] = cells[i], nil
+		} else {
+			r = t.formatRow(i, fmt, cfs)
+			t.alignPoints(i, r, cfs, tws)
+		}
+
+		writeCells(&fw, fitCells(r, ws, cfs, opt.Ellipsis), ws, cfs, fmt.RowLeftDelim, fmt.RowMidDelim, fmt.RowRightDelim)
+	}
+
+	writeRule(&fw, ws, fmt.BottomHoriz, fmt.BottomLeftHorizDelim, fmt.BottomMidHorizDelim, fmt.BottomRightHorizDelim)
+	if fw.nl && !opt.NoTrailingNewline {
+		fw.bw.WriteByte('\n')
+	}
+
+	return fw.bw.Flush()
+}
+
+// formatRow returns the lines of each value in the ith row.
+func (t *Table) formatRow(i int, fmt Format, cfs []ColFormat) [][]string {
+	r := make([][]string, 0, len(t.cols))
+	for j := 0; j < len(t.cols); j++ {
+		switch {
+		case t.cols[j].nulls[i]:
+			r = append(r, lines(fmt.Null))
+		case cfs[j].Formatter != nil:
+			r = append(r, lines(cfs[j].Formatter(t.cols[j].value(i))))
+		default:
+			r = append(r, lines(t.cols[j].str(i)))
+		}
+	}
+
+	return r
+}
+
+// alignPoints pads the numbers of the ith row so their tails are as wide
+// as the given tail widths, lining up the decimal points marked by each
+// column's Point. Columns having a negative tail width and values
+// without digits, such as NaN, are not padded.
+func (t *Table) alignPoints(i int, r [][]string, cfs []ColFormat, tws []int) {
+	for j := 0; j < len(r); j++ {
+		if 0 <= tws[j] && !t.cols[j].nulls[i] && len(r[j]) == 1 && hasDigit(r[j][0]) {
+			r[j][0] += padding(pointTail(r[j][0], cfs[j].Point), tws[j])
+		}
+	}
+}
+
+// fitCells returns a row of cells with each line wider than its column
+// wrapped or truncated.
+func fitCells(r [][]string, ws []int, cfs []ColFormat, ellipsis string) [][]string {
+	for j := 0; j < len(r); j++ {
+		ls := make([]string, 0, len(r[j]))
+		for _, line := range r[j] {
+			switch {
+			case width(line) <= ws[j]:
+				ls = append(ls, line)
+			case cfs[j].Wrap:
+				ls = append(ls, wrap(line, ws[j])...)
+			default:
+				ls = append(ls, truncate(line, ws[j], ellipsis))
+			}
+		}
+
+		r[j] = ls
+	}
+
+	return r
+}
+
+// lines returns the lines of a string with their control characters
+// expanded. Lines end in "\n", "\r\n", or a bare "\r".
+func lines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	ls := strings.Split(strings.ReplaceAll(s, "\r", "\n"), "\n")
+	for k := 0; k < len(ls); k++ {
+		ls[k] = expandControls(ls[k])
+	}
+
+	return ls
+}
+
+// writeCells writes a row of cells, each holding one or more lines, to
+// columns of given widths. The row is as tall as its tallest cell.
+func writeCells(fw *formatWriter, r [][]string, ws []int, cfs []ColFormat, left, mid, right string) {
+	height := 1
+	for j := 0; j < len(r); j++ {
+		if height < len(r[j]) {
+			height = len(r[j])
+		}
+	}
+
+	for k := 0; k < height; k++ {
+		fw.WriteString(left)
+		for j := 0; j < len(r); j++ {
+			if 0 < j {
+				fw.WriteString(mid)
+			}
+
+			var line string
+			if k < len(r[j]) {
+				line = r[j][k]
+			}
+
+			pad := padding(line, ws[j])
+			switch cfs[j].Align {
+			case AlignRight:
+				fw.WriteString(" " + pad + line + " ")
+			case AlignCenter:
+				fw.WriteString(" " + pad[:len(pad)/2] + line + pad[len(pad)/2:] + " ")
+			default:
+				fw.WriteString(" " + line + pad + " ")
+			}
+		}
+
+		fw.WriteString(right + "\n")
+	}
+}
+
+// writeRule writes a horizontal line spanning columns of given widths.
+// Nothing is written if the line is empty.
+func writeRule(fw *formatWriter, ws []int, horiz, left, mid, right string) {
+	if len(horiz) == 0 {
+		return
+	}
+
+	fw.WriteString(left)
+	if 0 < len(ws) {
+		fw.WriteString(rule(horiz, ws[0]+2))
+	}
+
+	for j := 1; j < len(ws); j++ {
+		fw.WriteString(mid + rule(horiz, ws[j]+2))
+	}
+
+	fw.WriteString(right + "\n")
+}
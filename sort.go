@@ -0,0 +1,164 @@
+package table
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKey defines a column to sort a table on and how its values are
+// ordered.
+type SortKey struct {
+	// Col is the index of the column to sort on. It is ignored if
+	// Name is provided.
+	Col int
+
+	// Name, if provided, is the name of the column to sort on.
+	// SortByName and StableByName require it.
+	Name string
+
+	// Desc orders values from greatest to least.
+	Desc bool
+
+	// NullsFirst orders missing values before all other values.
+	// Otherwise, missing values are ordered after all other values.
+	NullsFirst bool
+
+	// Fold orders strings without regard to case.
+	Fold bool
+
+	// Compare, if provided, returns -1, 0, or 1 if a value is less
+	// than, equal to, or greater than another. Missing values are
+	// never compared.
+	Compare func(a, b interface{}) int
+}
+
+// sorter orders the rows of a table by a list of sort keys. Rows are
+// ordered indirectly through a permutation of row indices.
+type sorter struct {
+	t      *Table
+	keys   []SortKey
+	folded [][]string // Lower case strings of each key having Fold set
+	perm   []int
+	stable bool
+}
+
+// SortBy sorts a table on several columns. Rows are ordered by the
+// first key, then by the second key among rows equal on the first,
+// and so on. The sort is not guaranteed to be stable. This panics if a
+// key's column index is out of range or its name is not found.
+func (t *Table) SortBy(keys ...SortKey) *Table {
+	return t.sortBy(keys, false)
+}
+
+// StableBy sorts a table on several columns as SortBy does, but rows
+// that are equal on every key retain their original order.
+func (t *Table) StableBy(keys ...SortKey) *Table {
+	return t.sortBy(keys, true)
+}
+
+// sortBy sorts a table on several columns.
+func (t *Table) sortBy(keys []SortKey, stable bool) *Table {
+	m, n := t.Dims()
+	keys = append(make([]SortKey, 0, len(keys)), keys...)
+	for x := 0; x < len(keys); x++ {
+		if keys[x].Name != "" {
+			j, err := t.colIndex(keys[x].Name)
+			if err != nil {
+				panic(err)
+			}
+
+			keys[x].Col = j
+		}
+	}
+
+	s := sorter{
+		t:      t,
+		keys:   keys,
+		folded: make([][]string, len(keys)),
+		perm:   make([]int, 0, m),
+		stable: stable,
+	}
+
+	for x, key := range keys {
+		if key.Col < 0 || n <= key.Col {
+			panic(errRange)
+		}
+
+		if v := &t.cols[key.Col]; key.Fold && v.typ == Str {
+			s.folded[x] = make([]string, 0, m)
+			for i := 0; i < m; i++ {
+				s.folded[x] = append(s.folded[x], strings.ToLower(v.strs[i]))
+			}
+		}
+	}
+
+	for i := 0; i < m; i++ {
+		s.perm = append(s.perm, i)
+	}
+
+	sort.Sort(&s)
+	for j := 0; j < n; j++ {
+		t.cols[j] = t.cols[j].permute(s.perm)
+	}
+
+	return t
+}
+
+// compare returns -1, 0, or 1 if the ith row is ordered before, with,
+// or after the kth row on the xth key.
+func (s *sorter) compare(x, i, k int) int {
+	var (
+		key = s.keys[x]
+		v   = &s.t.cols[key.Col]
+	)
+
+	switch {
+	case v.nulls[i] && v.nulls[k]:
+		return 0
+	case v.nulls[i] != v.nulls[k]:
+		if v.nulls[i] == key.NullsFirst {
+			return -1
+		}
+
+		return 1
+	}
+
+	var c int
+	switch {
+	case key.Compare != nil:
+		c = key.Compare(v.value(i), v.value(k))
+	case s.folded[x] != nil:
+		c = strings.Compare(s.folded[x][i], s.folded[x][k])
+	default:
+		c = v.compare(i, k)
+	}
+
+	if key.Desc {
+		c = -c
+	}
+
+	return c
+}
+
+// Len returns the number of rows. This implements sort.Interface.
+func (s *sorter) Len() int {
+	return len(s.perm)
+}
+
+// Less determines if the ath row is ordered before the bth row. This
+// implements sort.Interface.
+func (s *sorter) Less(a, b int) bool {
+	i, k := s.perm[a], s.perm[b]
+	for x := 0; x < len(s.keys); x++ {
+		if c := s.compare(x, i, k); c != 0 {
+			return c < 0
+		}
+	}
+
+	return s.stable && i < k
+}
+
+// Swap swaps the ath and bth rows. This implements sort.Interface.
+func (s *sorter) Swap(a, b int) {
+	s.perm[a], s.perm[b] = s.perm[b], s.perm[a]
+}
@@ -0,0 +1,618 @@
+package table
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SQLite is the SQLite dialect.
+	SQLite Dialect = iota
+
+	// PostgreSQL is the PostgreSQL dialect.
+	PostgreSQL
+
+	// MySQL is the MySQL dialect.
+	MySQL
+)
+
+// Dialect determines how sql statements are written for a database.
+type Dialect byte
+
+// SQLOptions holds settings for reading query results and writing sql
+// statements.
+type SQLOptions struct {
+	// Types, if provided, holds the type of each column. Columns of
+	// type Inv take their type from the database type or, if it is
+	// not recognized, from the first value that is not null.
+	Types Types
+
+	// TimeFmts are time formats tried before the sql date and time
+	// layouts and the default time formats when parsing time stamps
+	// returned as text.
+	TimeFmts []string
+
+	// Dialect determines the column types, quoting, and placeholders
+	// of written statements.
+	Dialect Dialect
+
+	// NoCreate omits the CREATE TABLE statement when writing.
+	NoCreate bool
+
+	// BatchSize is the number of rows in each INSERT statement. If not
+	// positive, each row has its own statement. WriteSQL reduces it to
+	// keep each statement within the dialect's limit on arguments.
+	BatchSize int
+}
+
+// TxBeginner begins transactions. It is implemented by *sql.DB and
+// *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// FromSQLRows returns a new table with data read from query results.
+// Columns are named as in the results and their types are determined
+// by the database types. Rows are read one at a time and nulls are read
+// as missing values. The rows are not closed. A single set of options
+// may be passed. This panics if more than one set of options is
+// provided.
+func FromSQLRows(rows *sql.Rows, opts ...SQLOptions) (*Table, error) {
+	opt := sqlOptions(opts)
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(names)
+	if 0 < len(opt.Types) && len(opt.Types) != n {
+		return nil, ErrDims
+	}
+
+	t := New(names)
+	for j := 0; j < n; j++ {
+		tp := Inv
+		if j < len(opt.Types) {
+			tp = opt.Types[j]
+		}
+
+		if tp == Inv {
+			tp = sqlType(cts[j])
+		}
+
+		switch tp {
+		case Inv:
+		case Int, Flt, Bool, Time, Str, Nil:
+			t.cols[j] = newVector(tp, 0)
+		default:
+			return nil, ErrType
+		}
+	}
+
+	var (
+		values = make([]interface{}, n)
+		dest   = make([]interface{}, n)
+		r      = make(Row, n)
+	)
+
+	for j := 0; j < n; j++ {
+		dest[j] = &values[j]
+	}
+
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < n; j++ {
+			x, err := sqlValue(values[j], t.cols[j].typ, opt.TimeFmts)
+			if err != nil {
+				return nil, newError(err, t, i, j, values[j])
+			}
+
+			r[j] = x
+		}
+
+		if err := t.TryAppend(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// sqlType returns the type of a column of query results. Inv is
+// returned if the type is not recognized.
+func sqlType(ct *sql.ColumnType) Type {
+	// Only the first word matters, as in "INT UNSIGNED" or "DOUBLE
+	// PRECISION", and any length, as in "VARCHAR(255)", is ignored.
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	if k := strings.IndexAny(name, " ("); 0 <= k {
+		name = name[:k]
+	}
+
+	switch name {
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT",
+		"INT2", "INT4", "INT8", "SMALLSERIAL", "SERIAL", "BIGSERIAL":
+		return Int
+	case "REAL", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "NUMERIC", "DECIMAL":
+		return Flt
+	case "BOOL", "BOOLEAN":
+		return Bool
+	case "DATE", "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		return Time
+	case "CHAR", "VARCHAR", "NCHAR", "NVARCHAR", "CHARACTER", "TEXT",
+		"TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "CLOB", "STRING", "UUID",
+		"JSON", "JSONB", "ENUM":
+		return Str
+	}
+
+	st := ct.ScanType()
+	if st == nil {
+		return Inv
+	}
+
+	switch st {
+	case timeType, reflect.TypeOf(sql.NullTime{}):
+		return Time
+	case reflect.TypeOf(sql.NullInt64{}), reflect.TypeOf(sql.NullInt32{}), reflect.TypeOf(sql.NullInt16{}):
+		return Int
+	case reflect.TypeOf(sql.NullFloat64{}):
+		return Flt
+	case reflect.TypeOf(sql.NullBool{}):
+		return Bool
+	case reflect.TypeOf(sql.NullString{}), reflect.TypeOf(sql.RawBytes{}), reflect.TypeOf([]byte{}):
+		return Str
+	}
+
+	if st.Kind() == reflect.Interface {
+		return Inv
+	}
+
+	return fieldType(st)
+}
+
+// sqlTimeFmts are the date and time layouts databases use when
+// returning time stamps as text. They are tried after any given time
+// formats and before the default time formats.
+var sqlTimeFmts = []string{"2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// sqlValue returns a value scanned from query results converted to fit
+// a column of a given type. If the type is Nil, the type is determined
+// by the value.
+func sqlValue(v interface{}, tp Type, timeFmts []string) (interface{}, error) {
+	if v == nil {
+		return Null, nil
+	}
+
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+
+	if tp == Nil {
+		switch v.(type) {
+		case int64:
+			tp = Int
+		case float64:
+			tp = Flt
+		case bool:
+			tp = Bool
+		case time.Time:
+			tp = Time
+		case string:
+			tp = Str
+		default:
+			return nil, ErrType
+		}
+	}
+
+	switch x := v.(type) {
+	case int64:
+		switch tp {
+		case Int:
+			return int(x), nil
+		case Flt:
+			return float64(x), nil
+		case Bool:
+			return x != 0, nil
+		case Str:
+			return strconv.FormatInt(x, 10), nil
+		}
+	case float64:
+		switch tp {
+		case Flt:
+			return x, nil
+		case Int:
+			if x == float64(int(x)) {
+				return int(x), nil
+			}
+		case Str:
+			return strconv.FormatFloat(x, 'f', -1, 64), nil
+		}
+	case bool:
+		switch tp {
+		case Bool:
+			return x, nil
+		case Int:
+			if x {
+				return 1, nil
+			}
+
+			return 0, nil
+		case Str:
+			return strconv.FormatBool(x), nil
+		}
+	case time.Time:
+		switch tp {
+		case Time:
+			return NewFTime(x), nil
+		case Str:
+			return x.Format(time.RFC3339Nano), nil
+		}
+	case string:
+		switch tp {
+		case Str:
+			return x, nil
+		case Int:
+			n, err := strconv.ParseInt(x, 10, strconv.IntSize)
+			if err != nil {
+				return nil, ErrType
+			}
+
+			return int(n), nil
+		case Flt:
+			f, err := strconv.ParseFloat(x, 64)
+			if err != nil {
+				return nil, ErrType
+			}
+
+			return f, nil
+		case Bool:
+			b, err := strconv.ParseBool(x)
+			if err != nil {
+				return nil, ErrType
+			}
+
+			return b, nil
+		case Time:
+			fmts := make([]string, 0, len(timeFmts)+len(sqlTimeFmts))
+			return ParseFTime(x, append(append(fmts, timeFmts...), sqlTimeFmts...)...)
+		}
+	}
+
+	return nil, ErrType
+}
+
+// CreateTableSQL returns a CREATE TABLE statement for a table of a
+// given name having the table's columns. A single set of options may
+// be passed. This panics if more than one set of options is provided.
+func (t *Table) CreateTableSQL(name string, opts ...SQLOptions) string {
+	opt := sqlOptions(opts)
+	var sb strings.Builder
+	sb.WriteString("CREATE TABLE " + quoteSQLIdent(name, opt.Dialect) + " (")
+	for j := 0; j < len(t.header); j++ {
+		if 0 < j {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString("\n\t" + quoteSQLIdent(t.header[j], opt.Dialect) + " " + sqlTypeName(t.cols[j].typ, opt.Dialect))
+	}
+
+	sb.WriteString("\n);")
+	return sb.String()
+}
+
+// WriteSQL creates a table of a given name in a database and inserts
+// each row within a single transaction using prepared statements, each
+// inserting a batch of rows. Missing values and floats that are not
+// finite are inserted as null. If any statement fails, the transaction
+// is rolled back. A single set of options may be passed. This panics if
+// more than one set of options is provided.
+func (t *Table) WriteSQL(ctx context.Context, db TxBeginner, name string, opts ...SQLOptions) error {
+	opt := sqlOptions(opts)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := t.insertSQL(ctx, tx, name, opt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertSQL creates a table of a given name and inserts each row within
+// a transaction.
+func (t *Table) insertSQL(ctx context.Context, tx *sql.Tx, name string, opt SQLOptions) error {
+	if !opt.NoCreate {
+		if _, err := tx.ExecContext(ctx, t.CreateTableSQL(name, opt)); err != nil {
+			return err
+		}
+	}
+
+	var (
+		m, n  = t.Dims()
+		batch = opt.BatchSize
+		stmt  *sql.Stmt
+		size  int // Number of rows inserted by the prepared statement
+	)
+
+	if batch <= 0 {
+		batch = 1
+	}
+
+	if limit := sqlMaxArgs(opt.Dialect); 0 < n && limit < batch*n {
+		if batch = limit / n; batch < 1 {
+			batch = 1
+		}
+	}
+
+	defer func() {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}()
+
+	args := make([]interface{}, 0, batch*n)
+	for i := 0; i < m; i += batch {
+		k := batch
+		if m-i < k {
+			k = m - i
+		}
+
+		if k != size {
+			if stmt != nil {
+				stmt.Close()
+			}
+
+			var err error
+			if stmt, err = tx.PrepareContext(ctx, t.insertSQLPrefix(name, opt.Dialect)+sqlPlaceholders(k, n, opt.Dialect)); err != nil {
+				return err
+			}
+
+			size = k
+		}
+
+		args = args[:0]
+		for r := i; r < i+k; r++ {
+			for j := 0; j < n; j++ {
+				args = append(args, sqlArg(&t.cols[j], r))
+			}
+		}
+
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sqlMaxArgs returns the greatest number of arguments a prepared
+// statement may take in a dialect.
+func sqlMaxArgs(d Dialect) int {
+	switch d {
+	case PostgreSQL, MySQL:
+		return 65535
+	default:
+		return 32766
+	}
+}
+
+// sqlPlaceholders returns the placeholders of m rows of n values each
+// in a dialect.
+func sqlPlaceholders(m, n int, d Dialect) string {
+	var sb strings.Builder
+	for i := 0; i < m; i++ {
+		if 0 < i {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteByte('(')
+		for j := 0; j < n; j++ {
+			if 0 < j {
+				sb.WriteString(", ")
+			}
+
+			if d == PostgreSQL {
+				sb.WriteString("$" + strconv.Itoa(i*n+j+1))
+			} else {
+				sb.WriteByte('?')
+			}
+		}
+
+		sb.WriteByte(')')
+	}
+
+	return sb.String()
+}
+
+// sqlArg returns the ith value of a vector as an argument to a
+// statement. Missing values and floats that are not finite are nil, as
+// they are NULL in sqlLiteral.
+func sqlArg(v *vector, i int) interface{} {
+	switch {
+	case v.nulls[i]:
+		return nil
+	case v.typ == Flt && (math.IsNaN(v.flts[i]) || math.IsInf(v.flts[i], 0)):
+		return nil
+	case v.typ == Time:
+		return v.times[i].time
+	default:
+		return v.value(i)
+	}
+}
+
+// WriteSQLTo writes a table to a writer as a CREATE TABLE statement
+// followed by INSERT statements holding each row. A single set of
+// options may be passed. This panics if more than one set of options is
+// provided.
+func (t *Table) WriteSQLTo(w io.Writer, name string, opts ...SQLOptions) error {
+	var (
+		opt    = sqlOptions(opts)
+		m, n   = t.Dims()
+		bw     = bufio.NewWriter(w)
+		prefix = t.insertSQLPrefix(name, opt.Dialect)
+		batch  = opt.BatchSize
+	)
+
+	if batch <= 0 {
+		batch = 1
+	}
+
+	if !opt.NoCreate {
+		bw.WriteString(t.CreateTableSQL(name, opt) + "\n")
+	}
+
+	for i := 0; i < m; i++ {
+		if i%batch == 0 {
+			bw.WriteString(prefix)
+		} else {
+			bw.WriteString(",\n\t")
+		}
+
+		bw.WriteByte('(')
+		for j := 0; j < n; j++ {
+			if 0 < j {
+				bw.WriteString(", ")
+			}
+
+			bw.WriteString(sqlLiteral(&t.cols[j], i, opt.Dialect))
+		}
+
+		bw.WriteByte(')')
+		if (i+1)%batch == 0 || i+1 == m {
+			bw.WriteString(";\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+// insertSQLPrefix returns the beginning of an INSERT statement up to
+// and including VALUES.
+func (t *Table) insertSQLPrefix(name string, d Dialect) string {
+	cols := make([]string, 0, len(t.header))
+	for j := 0; j < len(t.header); j++ {
+		cols = append(cols, quoteSQLIdent(t.header[j], d))
+	}
+
+	return "INSERT INTO " + quoteSQLIdent(name, d) + " (" + strings.Join(cols, ", ") + ") VALUES "
+}
+
+// sqlOptions returns the single set of options passed, if any.
+func sqlOptions(opts []SQLOptions) SQLOptions {
+	switch len(opts) {
+	case 0:
+		return SQLOptions{}
+	case 1:
+		return opts[0]
+	default:
+		panic(errVarCount)
+	}
+}
+
+// quoteSQLIdent returns an identifier quoted for a dialect.
+func quoteSQLIdent(s string, d Dialect) string {
+	if d == MySQL {
+		return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+	}
+
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// sqlTypeName returns the name of the column type holding values of a
+// given type in a dialect.
+func sqlTypeName(tp Type, d Dialect) string {
+	switch tp {
+	case Int:
+		if d == SQLite {
+			return "INTEGER"
+		}
+
+		return "BIGINT"
+	case Flt:
+		switch d {
+		case PostgreSQL:
+			return "DOUBLE PRECISION"
+		case MySQL:
+			return "DOUBLE"
+		default:
+			return "REAL"
+		}
+	case Bool:
+		return "BOOLEAN"
+	case Time:
+		switch d {
+		case PostgreSQL:
+			return "TIMESTAMPTZ"
+		case MySQL:
+			return "DATETIME(6)"
+		default:
+			return "TIMESTAMP"
+		}
+	case Str, Nil:
+		return "TEXT"
+	default:
+		panic(errType)
+	}
+}
+
+// sqlLiteral returns the ith value of a vector as a literal in a
+// dialect. Missing values and floats that are not finite are NULL.
+func sqlLiteral(v *vector, i int, d Dialect) string {
+	if v.nulls[i] {
+		return "NULL"
+	}
+
+	switch v.typ {
+	case Int:
+		return strconv.Itoa(v.ints[i])
+	case Flt:
+		if f := v.flts[i]; math.IsNaN(f) || math.IsInf(f, 0) {
+			return "NULL"
+		}
+
+		return strconv.FormatFloat(v.flts[i], 'g', -1, 64)
+	case Bool:
+		if v.bools[i] {
+			return "TRUE"
+		}
+
+		return "FALSE"
+	case Time:
+		if d == MySQL {
+			return "'" + v.times[i].time.UTC().Format("2006-01-02 15:04:05.999999") + "'"
+		}
+
+		return "'" + v.times[i].time.Format(time.RFC3339Nano) + "'"
+	case Str:
+		s := strings.ReplaceAll(v.strs[i], "'", "''")
+		if d == MySQL {
+			s = strings.ReplaceAll(s, `\`, `\\`)
+		}
+
+		return "'" + s + "'"
+	default:
+		panic(errType)
+	}
+}
@@ -0,0 +1,502 @@
+package table
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	// timeType is the type of time.Time.
+	timeType = reflect.TypeOf(time.Time{})
+
+	// ftimeType is the type of FTime.
+	ftimeType = reflect.TypeOf(FTime{})
+)
+
+// structField describes a struct field held in a column.
+type structField struct {
+	index  []int
+	name   string
+	typ    Type
+	tagged bool
+}
+
+// FromStructs returns a new table with one row per struct in a slice or
+// array of structs or pointers to structs. Each exported field is a
+// column named by the field's name or by its tag.
+//
+//	Name  string    `table:"name"` // Column "name"
+//	Notes string    `table:"-"`    // Omitted
+//	When  time.Time               // Column "When" of type Time
+//
+// Signed and unsigned integers are read as integers, float32 and
+// float64 as floats, and time.Time and FTime as time stamps. Pointers
+// to these are also supported, with nil read as a missing value, as is
+// a nil pointer to a struct. The fields of an untagged, embedded struct
+// or pointer to a struct are promoted to columns as encoding/json
+// promotes them: a field hides deeper fields of the same name, a tagged
+// field wins among fields of the same depth, and names still shared by
+// several fields are omitted. Fields behind a nil embedded pointer are
+// read as missing values. An error is returned if a field of any other
+// type is not omitted.
+func FromStructs(v interface{}) (*Table, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, ErrType
+	}
+
+	st := rv.Type().Elem()
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+
+	if st.Kind() != reflect.Struct {
+		return nil, ErrType
+	}
+
+	fs, err := structFields(st)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m = rv.Len()
+		h = make(Header, 0, len(fs))
+	)
+
+	for j := 0; j < len(fs); j++ {
+		h = append(h, fs[j].name)
+	}
+
+	t := newTable(h, m)
+	for j := 0; j < len(fs); j++ {
+		t.cols[j] = newVector(fs[j].typ, m)
+	}
+
+	for i := 0; i < m; i++ {
+		sv := rv.Index(i)
+		if sv.Kind() == reflect.Ptr {
+			if sv.IsNil() {
+				for j := 0; j < len(fs); j++ {
+					t.cols[j].append(Null)
+				}
+
+				continue
+			}
+
+			sv = sv.Elem()
+		}
+
+		for j := 0; j < len(fs); j++ {
+			fv, ok := fieldByIndex(sv, fs[j].index, false)
+			if !ok {
+				t.cols[j].append(Null)
+				continue
+			}
+
+			x, err := structValue(fv)
+			if err != nil {
+				return nil, newError(err, t, i, j, fv.Interface())
+			}
+
+			t.cols[j].append(x)
+		}
+	}
+
+	return t, nil
+}
+
+// structFields returns the fields of a struct type that are held in
+// columns, including the fields promoted from embedded structs.
+func structFields(st reflect.Type) ([]structField, error) {
+	fs, err := appendStructFields(nil, st, nil, map[reflect.Type]bool{st: true})
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]int) // Indices into fs of each name
+	for k := 0; k < len(fs); k++ {
+		byName[fs[k].name] = append(byName[fs[k].name], k)
+	}
+
+	kept := make([]structField, 0, len(fs))
+	for k := 0; k < len(fs); k++ {
+		if dominantField(fs, byName[fs[k].name]) == k {
+			kept = append(kept, fs[k])
+		}
+	}
+
+	return kept, nil
+}
+
+// appendStructFields appends the fields of a struct type, and those of
+// its embedded structs, to a list of fields. The index of each field is
+// prefixed by a given index. Embedded structs already being read are
+// skipped.
+func appendStructFields(fs []structField, st reflect.Type, prefix []int, reading map[reflect.Type]bool) ([]structField, error) {
+	for k := 0; k < st.NumField(); k++ {
+		f := st.Field(k)
+		tag := f.Tag.Get("table")
+		if tag == "-" {
+			continue
+		}
+
+		et := f.Type
+		if et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+
+		switch {
+		case f.Anonymous && f.PkgPath != "" && et.Kind() != reflect.Struct: // Unexported
+			continue
+		case !f.Anonymous && f.PkgPath != "": // Unexported
+			continue
+		}
+
+		index := append(append(make([]int, 0, len(prefix)+1), prefix...), k)
+		if f.Anonymous && tag == "" && et.Kind() == reflect.Struct && fieldType(et) == Inv {
+			if reading[et] {
+				continue
+			}
+
+			reading[et] = true
+			var err error
+			if fs, err = appendStructFields(fs, et, index, reading); err != nil {
+				return nil, err
+			}
+
+			delete(reading, et)
+			continue
+		}
+
+		if f.PkgPath != "" { // Unexported struct that is not promoted
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+
+		tp := fieldType(f.Type)
+		if tp == Inv {
+			return nil, &Error{Err: ErrField, Row: -1, Col: -1, Name: name, Type: Inv, Value: f.Type.String()}
+		}
+
+		fs = append(fs, structField{index: index, name: name, typ: tp, tagged: tag != ""})
+	}
+
+	return fs, nil
+}
+
+// dominantField returns the index of the field, of several sharing a
+// name, that is held in a column. This is the shallowest field or, if
+// several are shallowest, the only tagged one of them. If there is no
+// such field, -1 is returned.
+func dominantField(fs []structField, ks []int) int {
+	depth := len(fs[ks[0]].index)
+	for _, k := range ks {
+		if len(fs[k].index) < depth {
+			depth = len(fs[k].index)
+		}
+	}
+
+	d, count := -1, 0 // Dominant field and number of candidates
+	for _, k := range ks {
+		if len(fs[k].index) == depth && fs[k].tagged {
+			d, count = k, count+1
+		}
+	}
+
+	if count == 0 {
+		for _, k := range ks {
+			if len(fs[k].index) == depth {
+				d, count = k, count+1
+			}
+		}
+	}
+
+	if count != 1 {
+		return -1
+	}
+
+	return d
+}
+
+// fieldByIndex returns the nested field of a struct at an index. If a
+// nil embedded pointer is passed through, it is set to a new struct if
+// alloc is true and it may be set; otherwise, false is returned.
+func fieldByIndex(sv reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for x, k := range index {
+		if 0 < x && sv.Kind() == reflect.Ptr {
+			if sv.IsNil() {
+				if !alloc || !sv.CanSet() {
+					return reflect.Value{}, false
+				}
+
+				sv.Set(reflect.New(sv.Type().Elem()))
+			}
+
+			sv = sv.Elem()
+		}
+
+		sv = sv.Field(k)
+	}
+
+	return sv, true
+}
+
+// fieldType returns the type of a column holding values of a given Go
+// type. Inv is returned if the Go type is not supported.
+func fieldType(ft reflect.Type) Type {
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+
+	if ft == timeType || ft == ftimeType {
+		return Time
+	}
+
+	switch ft.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Int
+	case reflect.Float32, reflect.Float64:
+		return Flt
+	case reflect.Bool:
+		return Bool
+	case reflect.String:
+		return Str
+	default:
+		return Inv
+	}
+}
+
+// structValue returns the value of a struct field as held in a column.
+func structValue(fv reflect.Value) (interface{}, error) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return Null, nil
+		}
+
+		fv = fv.Elem()
+	}
+
+	switch fv.Type() {
+	case timeType:
+		return NewFTime(fv.Interface().(time.Time)), nil
+	case ftimeType:
+		return fv.Interface().(FTime), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := fv.Int()
+		if n < math.MinInt || math.MaxInt < n {
+			return nil, ErrRange
+		}
+
+		return int(n), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := fv.Uint()
+		if math.MaxInt < n {
+			return nil, ErrRange
+		}
+
+		return int(n), nil
+	case reflect.Float32:
+		// Use the shortest decimal representation of the float32
+		return strconv.ParseFloat(strconv.FormatFloat(fv.Float(), 'g', -1, 32), 64)
+	case reflect.Float64:
+		return fv.Float(), nil
+	case reflect.Bool:
+		return fv.Bool(), nil
+	case reflect.String:
+		return fv.String(), nil
+	default:
+		return nil, ErrType
+	}
+}
+
+// ToStructs fills a pointer to a slice of structs or pointers to
+// structs with one element per row, replacing the slice. Each field is
+// set from the column named by the field's name or by its tag as
+// described in FromStructs. Fields without a column are left as the zero
+// value, as are fields holding missing values other than pointers,
+// which are set to nil. Nil embedded pointers are set to new structs
+// when a field promoted through them holds a value. An error is returned
+// if a value does not fit its field or an embedded pointer to an
+// unexported struct must be set.
+func (t *Table) ToStructs(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return ErrType
+	}
+
+	var (
+		sv    = rv.Elem()
+		st    = sv.Type().Elem()
+		isPtr = st.Kind() == reflect.Ptr
+	)
+
+	if isPtr {
+		st = st.Elem()
+	}
+
+	if st.Kind() != reflect.Struct {
+		return ErrType
+	}
+
+	fs, err := structFields(st)
+	if err != nil {
+		return err
+	}
+
+	cols := make([]int, 0, len(fs)) // Column index of each field; -1 if not in the table
+	for k := 0; k < len(fs); k++ {
+		j, err := t.header.Index(fs[k].name)
+		switch err {
+		case nil:
+		case ErrColName:
+			j = -1
+		default:
+			return &Error{Err: err, Row: -1, Col: -1, Name: fs[k].name, Type: fs[k].typ}
+		}
+
+		cols = append(cols, j)
+	}
+
+	var (
+		m, _ = t.Dims()
+		out  = reflect.MakeSlice(sv.Type(), m, m)
+	)
+
+	for i := 0; i < m; i++ {
+		ev := out.Index(i)
+		if isPtr {
+			ev.Set(reflect.New(st))
+			ev = ev.Elem()
+		}
+
+		for k := 0; k < len(fs); k++ {
+			j := cols[k]
+			if j < 0 {
+				continue
+			}
+
+			x := t.cols[j].value(i)
+			fv, ok := fieldByIndex(ev, fs[k].index, x != Null)
+			switch {
+			case !ok && x == Null:
+				continue
+			case !ok:
+				e := newError(ErrField, t, i, j, x)
+				e.Type = fs[k].typ
+				return e
+			}
+
+			if err := setField(fv, x); err != nil {
+				e := newError(err, t, i, j, x)
+				e.Type = fs[k].typ
+				return e
+			}
+		}
+	}
+
+	sv.Set(out)
+	return nil
+}
+
+// setField sets a struct field to a value held in a column.
+func setField(fv reflect.Value, x interface{}) error {
+	if x == Null {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		p := reflect.New(fv.Type().Elem())
+		if err := setField(p.Elem(), x); err != nil {
+			return err
+		}
+
+		fv.Set(p)
+		return nil
+	}
+
+	switch fv.Type() {
+	case timeType, ftimeType:
+		ft, ok := x.(FTime)
+		if !ok {
+			return ErrType
+		}
+
+		if fv.Type() == timeType {
+			fv.Set(reflect.ValueOf(ft.time))
+		} else {
+			fv.Set(reflect.ValueOf(ft))
+		}
+
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := x.(int)
+		if !ok {
+			return ErrType
+		}
+
+		if fv.OverflowInt(int64(n)) {
+			return ErrRange
+		}
+
+		fv.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := x.(int)
+		if !ok {
+			return ErrType
+		}
+
+		if n < 0 || fv.OverflowUint(uint64(n)) {
+			return ErrRange
+		}
+
+		fv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, ok := x.(float64)
+		if !ok {
+			return ErrType
+		}
+
+		if fv.OverflowFloat(f) {
+			return ErrRange
+		}
+
+		fv.SetFloat(f)
+	case reflect.Bool:
+		b, ok := x.(bool)
+		if !ok {
+			return ErrType
+		}
+
+		fv.SetBool(b)
+	case reflect.String:
+		s, ok := x.(string)
+		if !ok {
+			return ErrType
+		}
+
+		fv.SetString(s)
+	default:
+		return ErrType
+	}
+
+	return nil
+}
@@ -2,8 +2,8 @@ package table
 
 import (
 	"bytes"
-	"encoding/csv"
-	"errors"
+	"encoding/json"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -14,16 +14,19 @@ import (
 )
 
 type (
-	// A Table holds tabular data.
+	// A Table holds tabular data. Each column is stored as a list of
+	// values of the column's type.
 	Table struct {
 		header Header
-		types  Types
-		body   Body
+		cols   []vector
 	}
 
 	// Filterer determines the criteria for retaining a row.
 	Filterer func(r Row) bool
 
+	// Formatter converts a value to a string.
+	Formatter func(v interface{}) string
+
 	// Generator defines the (i,j)th value.
 	Generator func(i, j int) interface{}
 
@@ -41,61 +44,25 @@ type (
 
 // New returns a new table.
 func New(h Header, r ...Row) *Table {
-	t := Table{
-		header: append(make(Header, 0, len(h)), h...),
-		types:  make(Types, 0, len(h)),
-		body:   make(Body, 0, len(r)*len(h)),
-	}
-
-	return t.Append(r...)
+	return newTable(h, len(r)).Append(r...)
 }
 
-// FromCSV returns a new table with data read from a csv file.
-func FromCSV(fileName string) (*Table, error) {
-	b, err := os.ReadFile(fileName)
-	if err != nil {
-		return nil, err
-	}
-
-	lines, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+// FromCSV returns a new table with data read from a csv file. Empty
+// fields are read as missing values.
+func FromCSV(fileName string, opts ...CSVOptions) (*Table, error) {
+	f, err := os.Open(fileName)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(lines) == 0 {
-		return New(nil), nil
-	}
-
-	t := Table{
-		header: Header(lines[0]),
-		types:  make(Types, 0, len(lines[0])),
-		body:   make(Body, 0, len(lines[0])*len(lines[1:])),
-	}
-
-	for i := 1; i < len(lines); i++ {
-		r := make(Row, 0, len(lines[i]))
-		for j := 0; j < len(lines[i]); j++ {
-			if n, err := strconv.ParseInt(lines[i][j], 10, strconv.IntSize); err == nil {
-				r = append(r, int(n))
-			} else if f, err := strconv.ParseFloat(lines[i][j], strconv.IntSize); err == nil {
-				r = append(r, f)
-			} else if b, err := strconv.ParseBool(lines[i][j]); err == nil {
-				r = append(r, b)
-			} else if ft, err := ParseFTime(lines[i][j]); err == nil {
-				r = append(r, ft)
-			} else {
-				r = append(r, lines[i][j])
-			}
-		}
-
-		t.Append(r)
-	}
-
-	return &t, nil
+	defer f.Close()
+	return FromCSVReader(f, opts...)
 }
 
 // FromJSON returns a new table with data parsed from a json-encoded
-// string. This string should adhere to the following format.
+// string. This string should adhere to the following format. Missing
+// values are encoded as null and floats that are not finite as the
+// strings "NaN", "Infinity", and "-Infinity".
 // 	{"header":["", ...],"types":[0, ...],"body":["", ...]}
 func FromJSON(s string) (*Table, error) {
 	var (
@@ -104,25 +71,55 @@ func FromJSON(s string) (*Table, error) {
 		body   = gjson.Get(s, "body").Array()
 		n      = len(header)
 		mn     = len(body)
-		t      = Table{
-			header: make(Header, 0, n),
-			types:  make(Types, 0, n),
-			body:   make(Body, 0, mn),
-		}
+		h      = make(Header, 0, n)
 	)
 
-	for i := 0; i < n; i++ {
-		t.header = append(t.header, header[i].String())
+	if len(types) != n || (0 < n && mn%n != 0) {
+		return nil, ErrDims
+	}
+
+	for j := 0; j < n; j++ {
+		h = append(h, header[j].String())
+	}
+
+	var m int
+	if 0 < n {
+		m = mn / n
+	}
+
+	t := newTable(h, m)
+	for j := 0; j < n; j++ {
+		switch tp := Type(types[j].Int()); tp {
+		case Int, Flt, Bool, Time, Str, Nil:
+			t.cols[j] = newVector(tp, m)
+		default:
+			return nil, ErrType
+		}
 	}
 
 	for i := 0; i < mn; i += n {
 		r := make(Row, 0, n)
 		for j := 0; j < n; j++ {
-			switch Type(types[j].Int()) {
+			if body[i+j].Type == gjson.Null {
+				r = append(r, Null)
+				continue
+			}
+
+			switch t.cols[j].typ {
 			case Int:
 				r = append(r, int(body[i+j].Int()))
 			case Flt:
-				r = append(r, float64(body[i+j].Float()))
+				if body[i+j].Type != gjson.String {
+					r = append(r, float64(body[i+j].Float()))
+					break
+				}
+
+				f, ok := jsonFloat(body[i+j].Str)
+				if !ok {
+					return nil, ErrType
+				}
+
+				r = append(r, f)
 			case Bool:
 				r = append(r, bool(body[i+j].Bool()))
 			case Time:
@@ -135,130 +132,82 @@ func FromJSON(s string) (*Table, error) {
 			case Str:
 				r = append(r, body[i+j].String())
 			default:
-				return nil, errors.New(errType)
+				return nil, ErrType
 			}
 		}
 
 		t.Append(r)
 	}
 
-	return &t, nil
+	return t, nil
 }
 
-// Generate returns a new table generated by a generator.
+// Generate returns a new table generated by a generator. This panics
+// if the generated values of a column are not of the same type.
 func Generate(h Header, m int, f Generator) *Table {
-	t := Table{
-		header: h.Copy(),
-		types:  make(Types, 0, len(h)),
-		body:   make(Body, 0, m*len(h)),
+	t, err := TryGenerate(h, m, f)
+	if err != nil {
+		panic(err)
 	}
 
-	if 0 < m {
-		for j := 0; j < len(h); j++ {
-			t.body = append(t.body, f(0, j))
-			t.types = append(t.types, Parse(t.body[j]))
-		}
+	return t
+}
 
-		for i := 1; i < m; i++ {
-			for j := 0; j < len(h); j++ {
-				t.body = append(t.body, f(i, j))
-				if Parse(t.body[i*len(h)+j]) != t.types[j] {
-					panic(errType)
-				}
+// TryGenerate returns a new table generated by a generator. An error is
+// returned if the generated values of a column are not of the same
+// type.
+func TryGenerate(h Header, m int, f Generator) (*Table, error) {
+	t := newTable(h, m)
+	for i := 0; i < m; i++ {
+		for j := 0; j < len(h); j++ {
+			v := f(i, j)
+			if unify(t.cols[j].typ, Parse(v)) == Inv {
+				return nil, newError(ErrType, t, i, j, v)
 			}
+
+			t.cols[j].append(v)
 		}
 	}
 
-	return &t
+	return t, nil
 }
 
-// --------------------------------------------------------------------
-// Methods
-// --------------------------------------------------------------------
-
-// Append several rows to a table.
-func (t *Table) Append(r ...Row) *Table {
-	if len(r) == 0 {
-		return t
+// newTable returns a new, empty table with the capacity to hold m
+// rows. Each column is of type Nil.
+func newTable(h Header, m int) *Table {
+	t := Table{
+		header: append(make(Header, 0, len(h)), h...),
+		cols:   make([]vector, 0, len(h)),
 	}
 
-	var i int
-	if len(t.body) == 0 {
-		if len(t.header) != len(r[0]) {
-			panic(errDims)
-		}
-
-		for j := 0; j < len(r[0]); j++ {
-			switch tp := Parse(r[0][j]); tp {
-			case Int, Flt, Bool, Time, Str:
-				t.types = append(t.types, tp)
-				t.body = append(t.body, r[0][j])
-			default:
-				panic(errType)
-			}
-		}
-
-		i++
+	for j := 0; j < len(h); j++ {
+		t.cols = append(t.cols, newVector(Nil, m))
 	}
 
-	for ; i < len(r); i++ {
-		if len(t.header) != len(r[i]) {
-			panic(errDims)
-		}
+	return &t
+}
 
-		for j := 0; j < len(r[i]); j++ {
-			if t.types[j] != Parse(r[i][j]) {
-				panic(errType)
-			}
+// --------------------------------------------------------------------
+// Methods
+// --------------------------------------------------------------------
 
-			t.body = append(t.body, r[i][j])
-		}
+// Append several rows to a table. Missing values may be appended to
+// any column. A column holding only missing values takes the type of
+// the first value appended to it that is not missing. This panics if
+// a row does not fit the table.
+func (t *Table) Append(r ...Row) *Table {
+	if err := t.TryAppend(r...); err != nil {
+		panic(err)
 	}
 
 	return t
 }
 
-// AppendCol appends a column to a table.
+// AppendCol appends a column to a table. This panics if the column
+// does not fit the table.
 func (t *Table) AppendCol(colName string, c Column) *Table {
-	m, n := t.Dims()
-	if n == 0 && 0 < len(c) {
-		tp := c.Type()
-		if tp == 0 {
-			panic(errType)
-		}
-
-		t.header = Header{colName}
-		t.types = []Type{tp}
-		t.body = append(make(Body, 0, len(c)), c...)
-		return t
-	}
-
-	if m != len(c) {
-		panic(errDims)
-	}
-
-	t.header = append(t.header, colName)
-	if 0 < m {
-		t.types = append(t.types, Parse(c[0]))
-		if m+len(t.body) <= cap(t.body) {
-			t.body = append(t.body, make([]interface{}, m)...)
-			for i := m - 1; 0 < i; i-- {
-				j0 := i * n
-				j1 := j0 + i
-				j2 := j1 + n
-				t.body[j2] = c[i]
-				copy(t.body[j1:j2], t.body[j0:j0+n])
-			}
-
-			t.body[n] = c[0]
-		} else {
-			b := make(Body, 0, (m+1)*n)
-			for i := 0; i < m; i++ {
-				b = append(append(b, t.body[i*n:(i+1)*n]...), c[i])
-			}
-
-			t.body = b
-		}
+	if err := t.TryAppendCol(colName, c); err != nil {
+		panic(err)
 	}
 
 	return t
@@ -266,7 +215,11 @@ func (t *Table) AppendCol(colName string, c Column) *Table {
 
 // Bool returns the (i,j)th value as a boolean.
 func (t *Table) Bool(i, j int) bool {
-	return t.body[i*len(t.header)+j].(bool)
+	if t.cols[j].typ != Bool {
+		panic(errType)
+	}
+
+	return t.cols[j].bools[i]
 }
 
 // Col returns the jth Column.
@@ -277,23 +230,19 @@ func (t *Table) Col(j int) Column {
 	}
 
 	c := make(Column, 0, m)
-	for ; j < len(t.body); j += n {
-		c = append(c, t.body[j])
+	for i := 0; i < m; i++ {
+		c = append(c, t.cols[j].value(i))
 	}
 
 	return c
 }
 
 // ColBools returns the jth column with each value cast as a boolean.
+// Missing values are returned as the zero value.
 func (t *Table) ColBools(j int) []bool {
-	m, n := t.Dims()
-	if n <= j {
-		panic(errRange)
-	}
-
-	c := make([]bool, 0, m)
-	for ; j < len(t.body); j += n {
-		c = append(c, t.body[j].(bool))
+	c, err := t.TryColBools(j)
+	if err != nil {
+		panic(err)
 	}
 
 	return c
@@ -301,65 +250,78 @@ func (t *Table) ColBools(j int) []bool {
 
 // ColType returns the type of the jth column.
 func (t *Table) ColType(j int) Type {
-	return t.types[j]
+	return t.cols[j].typ
 }
 
 // ColFloats returns the jth column with each value cast as a float.
+// Missing values are returned as the zero value.
 func (t *Table) ColFloats(j int) []float64 {
-	m, n := t.Dims()
-	if n <= j {
-		panic(errRange)
-	}
-
-	c := make([]float64, 0, m)
-	for ; j < len(t.body); j += n {
-		c = append(c, t.body[j].(float64))
+	c, err := t.TryColFloats(j)
+	if err != nil {
+		panic(err)
 	}
 
 	return c
 }
 
 // ColInts returns the jth column with each value cast as an integer.
+// Missing values are returned as the zero value.
 func (t *Table) ColInts(j int) []int {
-	m, n := t.Dims()
-	if n <= j {
-		panic(errRange)
-	}
-
-	c := make([]int, 0, m)
-	for ; j < len(t.body); j += n {
-		c = append(c, t.body[j].(int))
+	c, err := t.TryColInts(j)
+	if err != nil {
+		panic(err)
 	}
 
 	return c
 }
 
+// ColNullBools returns the jth column with each value cast as a boolean
+// together with a list indicating which values are not missing.
+func (t *Table) ColNullBools(j int) ([]bool, []bool) {
+	return t.ColBools(j), t.valid(j)
+}
+
+// ColNullFloats returns the jth column with each value cast as a float
+// together with a list indicating which values are not missing.
+func (t *Table) ColNullFloats(j int) ([]float64, []bool) {
+	return t.ColFloats(j), t.valid(j)
+}
+
+// ColNullInts returns the jth column with each value cast as an integer
+// together with a list indicating which values are not missing.
+func (t *Table) ColNullInts(j int) ([]int, []bool) {
+	return t.ColInts(j), t.valid(j)
+}
+
+// ColNullStrs returns the jth column with each value cast as a string
+// together with a list indicating which values are not missing.
+func (t *Table) ColNullStrs(j int) ([]string, []bool) {
+	return t.ColStrs(j), t.valid(j)
+}
+
+// ColNullTimes returns the jth column with each value cast as a time
+// object together with a list indicating which values are not missing.
+func (t *Table) ColNullTimes(j int) ([]time.Time, []bool) {
+	return t.ColTimes(j), t.valid(j)
+}
+
 // ColStrs returns the jth column with each value cast as a string.
+// Missing values are returned as the zero value.
 func (t *Table) ColStrs(j int) []string {
-	m, n := t.Dims()
-	if n <= j {
-		panic(errRange)
-	}
-
-	c := make([]string, 0, m)
-	for ; j < len(t.body); j += n {
-		c = append(c, t.body[j].(string))
+	c, err := t.TryColStrs(j)
+	if err != nil {
+		panic(err)
 	}
 
 	return c
 }
 
 // ColTimes returns the jth column with each value cast as a time
-// object.
+// object. Missing values are returned as the zero value.
 func (t *Table) ColTimes(j int) []time.Time {
-	m, n := t.Dims()
-	if n <= j {
-		panic(errRange)
-	}
-
-	c := make([]time.Time, 0, m)
-	for ; j < len(t.body); j += n {
-		c = append(c, t.body[j].(FTime).time)
+	c, err := t.TryColTimes(j)
+	if err != nil {
+		panic(err)
 	}
 
 	return c
@@ -367,15 +329,23 @@ func (t *Table) ColTimes(j int) []time.Time {
 
 // ColTypes returns the column types.
 func (t *Table) ColTypes() Types {
-	return append(make(Types, 0, len(t.types)), t.types...)
+	ts := make(Types, 0, len(t.cols))
+	for j := 0; j < len(t.cols); j++ {
+		ts = append(ts, t.cols[j].typ)
+	}
+
+	return ts
 }
 
 // Copy a table.
 func (t *Table) Copy() *Table {
 	cpy := Table{
 		header: append(make(Header, 0, len(t.header)), t.header...),
-		types:  append(make(Types, 0, len(t.types)), t.types...),
-		body:   append(make(Body, 0, len(t.body)), t.body...),
+		cols:   make([]vector, 0, len(t.cols)),
+	}
+
+	for j := 0; j < len(t.cols); j++ {
+		cpy.cols = append(cpy.cols, t.cols[j].copy())
 	}
 
 	return &cpy
@@ -384,157 +354,78 @@ func (t *Table) Copy() *Table {
 // Dims returns the number of rows and the number of columns in a
 // table body.
 func (t *Table) Dims() (int, int) {
-	m, n := len(t.body), len(t.header)
-	if 0 < n {
-		m /= n
+	var m int
+	if 0 < len(t.cols) {
+		m = t.cols[0].len()
 	}
 
-	return m, n
+	return m, len(t.header)
 }
 
 // Equal determines if two tables are equal.
 func (t *Table) Equal(tbl *Table) bool {
-	return t == tbl || (t.header.Equal(tbl.header) && t.types.Equal(tbl.types) && t.body.Equal(tbl.body))
-}
-
-// Filter applies a filterer on each row. Each row in which f
-// evaluates as true is retained; all other rows are discarded.
-func (t *Table) Filter(f Filterer) *Table {
-	m, n := t.Dims()
-	for i := (m - 1) * n; 0 <= i; i -= n {
-		if !f(Row(t.body[i : i+n])) {
-			t.body = append(t.body[:i], t.body[i+n:]...)
-		}
+	if t == tbl {
+		return true
 	}
 
-	if len(t.body) < n {
-		t.types = t.types[:0]
+	if !t.header.Equal(tbl.header) || len(t.cols) != len(tbl.cols) {
+		return false
 	}
 
-	return t
-}
+	for j := 0; j < len(t.cols); j++ {
+		if !t.cols[j].equal(&tbl.cols[j]) {
+			return false
+		}
+	}
 
-// Float returns the (i,j)th value as a float.
-func (t *Table) Float(i, j int) float64 {
-	return t.body[i*len(t.header)+j].(float64)
+	return true
 }
 
-// Format returns a formatted table given format rules.
-func (t *Table) Format(fmt Format) string {
+// Filter applies a filterer on each row. Each row in which f
+// evaluates as true is retained; all other rows are discarded.
+func (t *Table) Filter(f Filterer) *Table {
 	var (
 		m, n = t.Dims()
-		mn   = m * n
-		ws   = make([]int, 0, n) // Column widths
+		k    int // Number of rows retained
 	)
 
-	for j := 0; j < n; j++ {
-		ws = append(ws, len(t.header[j]))
-	}
-
-	b := t.body.Strings()
-	for i := 0; i < mn; i += n {
-		for j := 0; j < n; j++ {
-			if w := len(b[i+j]); ws[j] < w {
-				ws[j] = w
+	for i := 0; i < m; i++ {
+		if f(t.Row(i)) {
+			for j := 0; j < n; j++ {
+				t.cols[j].move(k, i)
 			}
-		}
-	}
-
-	var sb strings.Builder
-	sb.Grow(256) // TODO: Estimate how big a table may be
-	sb.WriteByte('\n')
-	if 0 < len(fmt.UpperHoriz) {
-		sb.WriteString(fmt.UpperLeftHorizDelim)
-		if 0 < n {
-			sb.WriteString(strings.Repeat(fmt.UpperHoriz, ws[0]+2))
-		}
 
-		for j := 1; j < n; j++ {
-			sb.WriteString(fmt.UpperMidHorizDelim + strings.Repeat(fmt.UpperHoriz, ws[j]+2))
-		}
-
-		sb.WriteString(fmt.UpperRightHorizDelim + "\n")
-	}
-
-	if 0 < len(fmt.HeaderLeftDelim) {
-		sb.WriteString(fmt.HeaderLeftDelim)
-	}
-
-	if 0 < n {
-		switch t.types[0] {
-		case Flt, Int:
-			sb.WriteString(strings.Repeat(" ", ws[0]-len(t.header[0])+1) + t.header[0] + " ")
-		case Bool, Time, Str:
-			sb.WriteString(" " + t.header[0] + strings.Repeat(" ", ws[0]-len(t.header[0])+1))
-		default:
-			panic(errType)
+			k++
 		}
 	}
 
-	for j := 1; j < n; j++ {
-		switch t.types[j] {
-		case Flt, Int:
-			sb.WriteString(fmt.HeaderMidDelim + strings.Repeat(" ", ws[j]-len(t.header[j])+1) + t.header[j] + " ")
-		case Bool, Time, Str:
-			sb.WriteString(fmt.HeaderMidDelim + " " + t.header[j] + strings.Repeat(" ", ws[j]-len(t.header[j])+1))
-		default:
-			panic(errType)
-		}
-	}
-
-	sb.WriteString(fmt.HeaderRightDelim + "\n")
-	if 0 < len(fmt.MiddleHoriz) {
-		sb.WriteString(fmt.MiddleLeftHorizDelim)
-		if 0 < n {
-			sb.WriteString(strings.Repeat(fmt.MiddleHoriz, ws[0]+2))
-		}
-
-		for j := 1; j < n; j++ {
-			sb.WriteString(fmt.MiddleMidHorizDelim + strings.Repeat(fmt.MiddleHoriz, ws[j]+2))
+	for j := 0; j < n; j++ {
+		if 0 < k {
+			t.cols[j].truncate(k)
+		} else {
+			t.cols[j] = newVector(Nil, 0)
 		}
-
-		sb.WriteString(fmt.MiddleRightHorizDelim + "\n")
 	}
 
-	for i := 0; i < mn; i += n {
-		switch t.types[0] {
-		case Flt, Int:
-			sb.WriteString(fmt.RowLeftDelim + strings.Repeat(" ", ws[0]-len(b[i])+1) + b[i] + " ")
-		case Bool, Time, Str:
-			sb.WriteString(fmt.RowLeftDelim + " " + b[i] + strings.Repeat(" ", ws[0]-len(b[i])+1))
-		default:
-			panic(errType)
-		}
-
-		for j := 1; j < n; j++ {
-			ij := i + j
-			switch t.types[j] {
-			case Flt, Int:
-				sb.WriteString(fmt.RowMidDelim + strings.Repeat(" ", ws[j]-len(b[ij])+1) + b[ij] + " ")
-			case Bool, Time, Str:
-				sb.WriteString(fmt.RowMidDelim + " " + b[ij] + strings.Repeat(" ", ws[j]-len(b[ij])+1))
-			default:
-				panic(errType)
-			}
-		}
+	return t
+}
 
-		sb.WriteString(fmt.RowRightDelim + "\n")
+// Float returns the (i,j)th value as a float.
+func (t *Table) Float(i, j int) float64 {
+	if t.cols[j].typ != Flt {
+		panic(errType)
 	}
 
-	// Bottom horizontal line
-	if 0 < len(fmt.BottomHoriz) {
-		sb.WriteString(fmt.BottomLeftHorizDelim)
-		if 0 < n {
-			sb.WriteString(strings.Repeat(fmt.BottomHoriz, ws[0]+2))
-		}
-
-		for j := 1; j < n; j++ {
-			sb.WriteString(fmt.BottomMidHorizDelim + strings.Repeat(fmt.BottomHoriz, ws[j]+2))
-		}
-
-		sb.WriteString(fmt.BottomRightHorizDelim + "\n")
-	}
+	return t.cols[j].flts[i]
+}
 
+// Format returns a formatted table given format rules. See
+// WriteFormatTo for how values are displayed. A single set of options
+// may be passed. This panics if more than one set of options is
+// provided.
+func (t *Table) Format(fmt Format, opts ...FormatOptions) string {
+	var sb strings.Builder
+	t.WriteFormatTo(&sb, fmt, opts...)
 	return sb.String()
 }
 
@@ -543,32 +434,38 @@ func (t *Table) Header() Header {
 	return append(make(Header, 0, len(t.header)), t.header...)
 }
 
-// Insert a row into the ith position.
+// Insert a row into the ith position. This panics if the position is
+// out of range or the row does not fit the table.
 func (t *Table) Insert(i int, r Row) *Table {
-	m, n := t.Dims()
-	for i := 0; i < n; i++ {
-		t.body = append(t.body, nil)
+	if err := t.TryInsert(i, r); err != nil {
+		panic(err)
 	}
 
-	var (
-		j  = i * n
-		k  = j + n
-		mn = m * n
-	)
-
-	copy(t.body[k:mn+n], t.body[j:mn])
-	copy(t.body[j:k], r)
-	return t.Append(r).Swap(i, m)
+	return t
 }
 
-// InsertCol inserts a column into the jth position.
+// InsertCol inserts a column into the jth position. This panics if the
+// position is out of range or the column does not fit the table.
 func (t *Table) InsertCol(j int, colName string, c Column) *Table {
-	return t.AppendCol(colName, c).SwapCols(j, len(t.header))
+	if err := t.TryInsertCol(j, colName, c); err != nil {
+		panic(err)
+	}
+
+	return t
 }
 
 // Int returns the (i,j)th value as an integer.
 func (t *Table) Int(i, j int) int {
-	return t.body[i*len(t.header)+j].(int)
+	if t.cols[j].typ != Int {
+		panic(errType)
+	}
+
+	return t.cols[j].ints[i]
+}
+
+// IsNull determines if the (i,j)th value is missing.
+func (t *Table) IsNull(i, j int) bool {
+	return t.cols[j].nulls[i]
 }
 
 // Join several tables having the same number of rows into one.
@@ -578,7 +475,6 @@ func Join(tbl ...*Table) *Table {
 	}
 
 	m0, n := tbl[0].Dims()
-	ns := append(make([]int, 0, len(tbl)), n)
 	for i := 1; i < len(tbl); i++ {
 		mi, ni := tbl[i].Dims()
 		if m0 != mi {
@@ -586,113 +482,90 @@ func Join(tbl ...*Table) *Table {
 		}
 
 		n += ni
-		ns = append(ns, ni)
 	}
 
-	h := make(Header, 0, n)
-	for i := 0; i < len(tbl); i++ {
-		h = append(h, tbl[i].header...)
+	t := Table{
+		header: make(Header, 0, n),
+		cols:   make([]vector, 0, n),
 	}
 
-	f := func(i, j int) interface{} {
-		// TODO: Computing k and modifying j is slow. Figure out how to map j to k and j to ns[k].
-		var k int
-		for ; k < len(tbl) && ns[k] <= j; k++ {
-			j -= ns[k]
+	for i := 0; i < len(tbl); i++ {
+		t.header = append(t.header, tbl[i].header...)
+		for j := 0; j < len(tbl[i].cols); j++ {
+			t.cols = append(t.cols, tbl[i].cols[j].copy())
 		}
-
-		return tbl[k].body[i*ns[k]+j]
 	}
 
-	return Generate(h, m0, f)
+	return &t
 }
 
-// JSON returns a json-encoded string representing a table.
+// JSON returns a json-encoded string representing a table. Missing
+// values are encoded as null. Floats that are not finite are encoded as
+// the strings "NaN", "Infinity", and "-Infinity", which FromJSON reads
+// back as floats.
 func (t *Table) JSON() string {
 	m, n := t.Dims()
-	if n == 0 {
-		return `{"header":[],"types":[],"body":[]}`
+	b := make([]byte, 0, (m+3)*n*8)
+	b = append(b, `{"header":[`...)
+	for j := 0; j < n; j++ {
+		if 0 < j {
+			b = append(b, ',')
+		}
+
+		b = appendJSONString(b, t.header[j])
 	}
 
-	var sb strings.Builder
-	sb.Grow((m + 3) * n * 8)
-	sb.WriteString(`{"header":["` + strings.Join([]string(t.header), `","`) + `"],"types":[` + strconv.Itoa(int(t.types[0])))
-	for j := 1; j < n; j++ {
-		sb.WriteString(`,` + strconv.Itoa(int(t.types[j])))
-	}
-
-	sb.WriteString(`],"body":[`)
-	if 0 < m {
-		switch t.types[0] {
-		case Int:
-			sb.WriteString(strconv.FormatInt(int64(t.body[0].(int)), 10))
-		case Flt:
-			sb.WriteString(strconv.FormatFloat(t.body[0].(float64), 'f', -1, 64))
-		case Bool:
-			sb.WriteString(strconv.FormatBool(t.body[0].(bool)))
-		case Time:
-			sb.WriteString(`"` + t.body[0].(FTime).String() + `"`)
-		case Str:
-			sb.WriteString(`"` + t.body[0].(string) + `"`)
-		default:
+	b = append(b, `],"types":[`...)
+	for j := 0; j < n; j++ {
+		if 0 < j {
+			b = append(b, ',')
 		}
 
-		for j := 1; j < n; j++ {
-			switch t.types[j] {
-			case Int:
-				sb.WriteString(`,` + strconv.FormatInt(int64(t.body[j].(int)), 10))
-			case Flt:
-				sb.WriteString(`,` + strconv.FormatFloat(t.body[j].(float64), 'f', -1, 64))
-			case Bool:
-				sb.WriteString(`,` + strconv.FormatBool(t.body[j].(bool)))
-			case Time:
-				sb.WriteString(`,"` + t.body[j].(FTime).String() + `"`)
-			case Str:
-				sb.WriteString(`,"` + t.body[j].(string) + `"`)
-			default:
+		b = strconv.AppendInt(b, int64(t.cols[j].typ), 10)
+	}
+
+	b = append(b, `],"body":[`...)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			if 0 < i || 0 < j {
+				b = append(b, ',')
 			}
-		}
 
-		for i, mn := n, m*n; i < mn; i += n {
-			for j := 0; j < n; j++ {
-				switch t.types[j] {
-				case Int:
-					sb.WriteString(`,` + strconv.FormatInt(int64(t.body[i+j].(int)), 10))
-				case Flt:
-					sb.WriteString(`,` + strconv.FormatFloat(t.body[i+j].(float64), 'f', -1, 64))
-				case Bool:
-					sb.WriteString(`,` + strconv.FormatBool(t.body[i+j].(bool)))
-				case Time:
-					sb.WriteString(`,"` + t.body[i+j].(FTime).String() + `"`)
-				case Str:
-					sb.WriteString(`,"` + t.body[i+j].(string) + `"`)
-				default:
-				}
+			v := &t.cols[j]
+			switch {
+			case v.nulls[i]:
+				b = append(b, "null"...)
+			case v.typ == Flt && (math.IsNaN(v.flts[i]) || math.IsInf(v.flts[i], 0)):
+				b = appendJSONFloat(b, v.flts[i])
+			case v.typ == Flt:
+				b = strconv.AppendFloat(b, v.flts[i], 'f', -1, 64)
+			default:
+				b = appendJSONValue(b, v.value(i))
 			}
 		}
 	}
 
-	sb.WriteString(`]}`)
-	return sb.String()
+	b = append(b, `]}`...)
+	return string(b)
 }
 
-// Map mutates each row in a table and updates the column types.
-func (t *Table) Map(f Mapper) *Table {
-	n := len(t.header)
-	f(Row(t.body[:n]))
-
-	var i int
-	for ; i < n; i++ {
-		t.types[i] = Parse(t.body[i])
+// JSONIndent returns a json-encoded string representing a table as JSON
+// does. Each element begins on a new line starting with prefix followed
+// by one or more copies of indent according to the nesting.
+func (t *Table) JSONIndent(prefix, indent string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(t.JSON()), prefix, indent); err != nil {
+		panic(err) // JSON always returns valid json
 	}
 
-	for ; i < len(t.body); i += n {
-		f(Row(t.body[i : i+n]))
-		for j := 0; j < n; j++ {
-			if Parse(t.body[i+j]) != t.types[j] {
-				panic(errType)
-			}
-		}
+	return buf.String()
+}
+
+// Map mutates each row in a table and updates the column types. This
+// panics if the mutated rows do not fit the table.
+func (t *Table) Map(f Mapper) *Table {
+	if err := t.TryMap(f); err != nil {
+		panic(err)
 	}
 
 	return t
@@ -706,55 +579,38 @@ func (t *Table) MarshalJSON() ([]byte, error) {
 
 // Reduce returns a row that is the product of applying a reducer on
 // each row in a table. A copy of the first row is used as the
-// accumulator.
+// accumulator. Reducers should check for missing values with IsNull.
 func (t *Table) Reduce(f Reducer) Row {
 	m, n := t.Dims()
-	r := make(Row, 0, n)
-
-	if 0 < m {
-		r = append(r, t.body[:n]...)
+	if m == 0 {
+		return make(Row, 0, n)
 	}
 
-	for i := n; i < len(t.body); i += n {
-		f(r, Row(t.body[i:i+n]))
+	r := t.Row(0)
+	for i := 1; i < m; i++ {
+		f(r, t.Row(i))
 	}
 
 	return r
 }
 
-// Remove removes and returns the ith row from a table.
+// Remove removes and returns the ith row from a table. This panics if
+// the row is out of range.
 func (t *Table) Remove(i int) Row {
-	n := len(t.header)
-	r := NewRow(t.body[i*n : (i+1)*n]...)
-
-	t.body = append(t.body[:i*n], t.body[(i+1)*n:]...)
-	if len(t.body) < n {
-		t.types = t.types[:0]
+	r, err := t.TryRemove(i)
+	if err != nil {
+		panic(err)
 	}
 
 	return r
 }
 
-// RemoveCol removes and returns the jth column from a table.
+// RemoveCol removes and returns the jth column from a table. This
+// panics if the column is out of range.
 func (t *Table) RemoveCol(j int) (string, Column) {
-	var (
-		m, n   = t.Dims()
-		name   = t.header[j]
-		column = make([]interface{}, 0, m)
-	)
-
-	t.header = append(t.header[:j], t.header[j+1:]...)
-	t.types = append(t.types[:j], t.types[j+1:]...)
-	if 0 < m {
-		for i := 0; i+1 < m; i++ {
-			ij := i*n + j
-			column = append(column, t.body[ij])
-			copy(t.body[ij-i:ij-i+n-1], t.body[ij+1:ij+n])
-		}
-
-		ij := len(t.body) - n + j
-		column = append(column, t.body[ij])
-		t.body = append(t.body[:ij-m+1], t.body[ij+1:]...)
+	name, column, err := t.TryRemoveCol(j)
+	if err != nil {
+		panic(err)
 	}
 
 	return name, column
@@ -762,29 +618,32 @@ func (t *Table) RemoveCol(j int) (string, Column) {
 
 // Row returns the ith row from a table.
 func (t *Table) Row(i int) Row {
-	n := len(t.header)
-	return NewRow(t.body[i*n : (i+1)*n]...)
+	r := make(Row, 0, len(t.cols))
+	for j := 0; j < len(t.cols); j++ {
+		r = append(r, t.cols[j].value(i))
+	}
+
+	return r
 }
 
 // Rows returns a list of all the rows in a table.
 func (t *Table) Rows() []Row {
-	m, n := t.Dims()
+	m, _ := t.Dims()
 	rs := make([]Row, 0, m)
-
-	for i, mn := 0, m*n; i < mn; i += n {
-		rs = append(rs, NewRow(t.body[i:i+n]...))
+	for i := 0; i < m; i++ {
+		rs = append(rs, t.Row(i))
 	}
 
 	return rs
 }
 
-// Set the (i,j)th value in a table.
+// Set the (i,j)th value in a table. A missing value may be set in any
+// column. This panics if the value does not fit the column.
 func (t *Table) Set(i, j int, v interface{}) *Table {
-	if t.types[j] != Parse(v) {
-		panic(errType)
+	if err := t.TrySet(i, j, v); err != nil {
+		panic(err)
 	}
 
-	t.body[i*len(t.header)+j] = v
 	return t
 }
 
@@ -804,94 +663,51 @@ func (t *Table) SetHeader(h Header) *Table {
 	return t
 }
 
-// Sort sorts a table on the jth column.
+// Sort sorts a table on the jth column. Missing values are sorted
+// last. The sort is not guaranteed to be stable.
 func (t *Table) Sort(j int) *Table {
-	return t.Stable(j) // TODO: Replace.
+	return t.SortBy(SortKey{Col: j})
 }
 
-// Stable sorts a table on the jth column.
+// Stable sorts a table on the jth column. Missing values are sorted
+// last. Rows having equal values retain their original order.
 func (t *Table) Stable(j int) *Table {
-	m, n := t.Dims()
-	for k := 1; k < m; k++ {
-		for i := k - 1; 0 <= i; i-- {
-			switch t.types[j] {
-			case Int:
-				if t.body[i*n+j].(int) <= t.body[(i+1)*n+j].(int) {
-					i = -1
-				}
-			case Flt:
-				if t.body[i*n+j].(float64) <= t.body[(i+1)*n+j].(float64) {
-					i = -1
-				}
-			case Bool:
-				if !t.body[i*n+j].(bool) || t.body[(i+1)*n+j].(bool) {
-					i = -1
-				}
-			case Time:
-				if t.body[i*n+j].(FTime).Compare(t.body[(i+1)*n+j].(FTime)) <= 0 {
-					i = -1
-				}
-			case Str:
-				if t.body[i*n+j].(string) <= t.body[(i+1)*n+j].(string) {
-					i = -1
-				}
-			default:
-				panic(errType)
-			}
-
-			if 0 <= i {
-				t.Swap(i, i+1)
-			}
-		}
-	}
-
-	return t
+	return t.StableBy(SortKey{Col: j})
 }
 
 // Str returns the (i,j)th value as a string.
 func (t *Table) Str(i, j int) string {
-	return t.body[i*len(t.header)+j].(string)
+	if t.cols[j].typ != Str {
+		panic(errType)
+	}
+
+	return t.cols[j].strs[i]
 }
 
 // String returns a string representing a table.
 func (t *Table) String() string {
-	return "[" + t.header.String() + " | " + t.body.String() + "]"
+	m, n := t.Dims()
+	b := make(Body, 0, m*n)
+	for i := 0; i < m; i++ {
+		b = append(b, t.Row(i)...)
+	}
+
+	return "[" + t.header.String() + " | " + b.String() + "]"
 }
 
 // Strings returns a list of string lists. The first string list is
-// the header.
+// the header. Missing values are converted to the empty string.
 func (t *Table) Strings() [][]string {
 	var (
 		m, n = t.Dims()
 		ss   = make([][]string, 0, m+1)
-		h    = make([]string, 0, n)
 	)
 
-	for j := 0; j < len(t.header); j++ {
-		h = append(h, t.header[j])
-	}
-
-	ss = append(ss, h)
-	for i, mn := 0, m*n; i < mn; i += n {
+	ss = append(ss, t.header.Strings())
+	for i := 0; i < m; i++ {
 		r := make([]string, 0, n)
 		for j := 0; j < n; j++ {
-			switch t.types[j] {
-			case Bool:
-				r = append(r, strconv.FormatBool(t.body[i+j].(bool)))
-			case Flt:
-				if v := t.body[i+j].(float64); v == float64(int64(v)) {
-					r = append(r, strconv.FormatFloat(v, 'f', 1, 64)) // Forces f.0 when value is an integer
-				} else {
-					r = append(r, strconv.FormatFloat(v, 'f', -1, 64))
-				}
-			case Int:
-				r = append(r, strconv.Itoa(t.body[i+j].(int)))
-			case Time:
-				r = append(r, t.body[i+j].(FTime).String())
-			case Str:
-				r = append(r, t.body[i+j].(string))
-			default:
-			}
+			r = append(r, t.cols[j].str(i))
 		}
 
 		ss = append(ss, r)
@@ -900,23 +716,21 @@ func (t *Table) Strings() [][]string {
 	return ss
 }
 
-// Swap swaps two rows in a table.
+// Swap swaps two rows in a table. This panics if either row is out of
+// range.
 func (t *Table) Swap(i, j int) *Table {
-	for k, n := 0, len(t.header); k < n; k++ {
-		ik, jk := i*n+k, j*n+k
-		t.body[ik], t.body[jk] = t.body[jk], t.body[ik]
+	if err := t.TrySwap(i, j); err != nil {
+		panic(err)
 	}
 
 	return t
 }
 
-// SwapCols swaps two columns in a table.
+// SwapCols swaps two columns in a table. This panics if either column
+// is out of range.
 func (t *Table) SwapCols(i, j int) *Table {
-	t.header[i], t.header[j] = t.header[j], t.header[i]
-	t.types[i], t.types[j] = t.types[j], t.types[i]
-
-	for kn, n := 0, len(t.header); kn < len(t.body); kn += n {
-		t.body[kn+i], t.body[kn+j] = t.body[kn+j], t.body[kn+i]
+	if err := t.TrySwapCols(i, j); err != nil {
+		panic(err)
 	}
 
 	return t
@@ -924,7 +738,372 @@ func (t *Table) SwapCols(i, j int) *Table {
 
 // Time the (i,j)th value as a time object.
 func (t *Table) Time(i, j int) time.Time {
-	return t.body[i*len(t.header)+j].(FTime).time
+	if t.cols[j].typ != Time {
+		panic(errType)
+	}
+
+	return t.cols[j].times[i].time
+}
+
+// TryAppend appends several rows to a table as Append does. An error
+// is returned if a row does not fit the table, in which case no rows
+// are appended. The error's row index is the index the row would have
+// had in the table.
+func (t *Table) TryAppend(r ...Row) error {
+	var (
+		m, n = t.Dims()
+		ts   = t.ColTypes()
+	)
+
+	for i := 0; i < len(r); i++ {
+		if n != len(r[i]) {
+			return newError(ErrDims, t, m+i, -1, r[i])
+		}
+
+		for j := 0; j < n; j++ {
+			tp := unify(ts[j], Parse(r[i][j]))
+			if tp == Inv {
+				e := newError(ErrType, t, m+i, j, r[i][j])
+				e.Type = ts[j]
+				return e
+			}
+
+			ts[j] = tp
+		}
+	}
+
+	for i := 0; i < len(r); i++ {
+		for j := 0; j < n; j++ {
+			t.cols[j].append(r[i][j])
+		}
+	}
+
+	return nil
+}
+
+// TryAppendCol appends a column to a table as AppendCol does. An error
+// is returned if the column does not fit the table.
+func (t *Table) TryAppendCol(colName string, c Column) error {
+	return t.TryInsertCol(len(t.header), colName, c)
+}
+
+// TryBool returns the (i,j)th value as a boolean. An error is returned
+// if the value is not in the table or the column is not of type Bool.
+func (t *Table) TryBool(i, j int) (bool, error) {
+	if err := t.check(i, j, Bool); err != nil {
+		return false, err
+	}
+
+	return t.cols[j].bools[i], nil
+}
+
+// TryColBools returns the jth column as ColBools does. An error is
+// returned if the column is not in the table or is neither of type Bool
+// nor of type Nil.
+func (t *Table) TryColBools(j int) ([]bool, error) {
+	if err := t.checkCol(j, Bool); err != nil {
+		return nil, err
+	}
+
+	if t.cols[j].typ == Nil {
+		return make([]bool, t.cols[j].len()), nil
+	}
+
+	return append(make([]bool, 0, t.cols[j].len()), t.cols[j].bools...), nil
+}
+
+// TryColFloats returns the jth column as ColFloats does. An error is
+// returned if the column is not in the table or is neither of type Flt
+// nor of type Nil.
+func (t *Table) TryColFloats(j int) ([]float64, error) {
+	if err := t.checkCol(j, Flt); err != nil {
+		return nil, err
+	}
+
+	if t.cols[j].typ == Nil {
+		return make([]float64, t.cols[j].len()), nil
+	}
+
+	return append(make([]float64, 0, t.cols[j].len()), t.cols[j].flts...), nil
+}
+
+// TryColInts returns the jth column as ColInts does. An error is
+// returned if the column is not in the table or is neither of type Int
+// nor of type Nil.
+func (t *Table) TryColInts(j int) ([]int, error) {
+	if err := t.checkCol(j, Int); err != nil {
+		return nil, err
+	}
+
+	if t.cols[j].typ == Nil {
+		return make([]int, t.cols[j].len()), nil
+	}
+
+	return append(make([]int, 0, t.cols[j].len()), t.cols[j].ints...), nil
+}
+
+// TryColStrs returns the jth column as ColStrs does. An error is
+// returned if the column is not in the table or is neither of type Str
+// nor of type Nil.
+func (t *Table) TryColStrs(j int) ([]string, error) {
+	if err := t.checkCol(j, Str); err != nil {
+		return nil, err
+	}
+
+	if t.cols[j].typ == Nil {
+		return make([]string, t.cols[j].len()), nil
+	}
+
+	return append(make([]string, 0, t.cols[j].len()), t.cols[j].strs...), nil
+}
+
+// TryColTimes returns the jth column as ColTimes does. An error is
+// returned if the column is not in the table or is neither of type
+// Time nor of type Nil.
+func (t *Table) TryColTimes(j int) ([]time.Time, error) {
+	if err := t.checkCol(j, Time); err != nil {
+		return nil, err
+	}
+
+	c := make([]time.Time, t.cols[j].len())
+	if t.cols[j].typ == Time {
+		for i := 0; i < len(c); i++ {
+			c[i] = t.cols[j].times[i].time
+		}
+	}
+
+	return c, nil
+}
+
+// TryFloat returns the (i,j)th value as a float. An error is returned
+// if the value is not in the table or the column is not of type Flt.
+func (t *Table) TryFloat(i, j int) (float64, error) {
+	if err := t.check(i, j, Flt); err != nil {
+		return 0, err
+	}
+
+	return t.cols[j].flts[i], nil
+}
+
+// TryInsert inserts a row into the ith position as Insert does. An
+// error is returned if the position is out of range or the row does not
+// fit the table, in which case the table is not changed.
+func (t *Table) TryInsert(i int, r Row) error {
+	m, n := t.Dims()
+	if i < 0 || m < i {
+		return newError(ErrRange, nil, i, -1, nil)
+	}
+
+	if err := t.TryAppend(r); err != nil {
+		err.(*Error).Row = i
+		return err
+	}
+
+	for j := 0; j < n; j++ {
+		for k := m; i < k; k-- {
+			t.cols[j].swap(k, k-1)
+		}
+	}
+
+	return nil
+}
+
+// TryInsertCol inserts a column into the jth position as InsertCol
+// does. An error is returned if the position is out of range or the
+// column does not fit the table, in which case the table is not
+// changed.
+func (t *Table) TryInsertCol(j int, colName string, c Column) error {
+	m, n := t.Dims()
+	if j < 0 || n < j {
+		e := newError(ErrRange, nil, -1, j, nil)
+		e.Name = colName
+		return e
+	}
+
+	if 0 < n && m != len(c) {
+		e := newError(ErrDims, nil, -1, j, c)
+		e.Name = colName
+		return e
+	}
+
+	tp := Nil
+	for i := 0; i < len(c); i++ {
+		next := unify(tp, Parse(c[i]))
+		if next == Inv {
+			e := newError(ErrType, nil, i, j, c[i])
+			e.Name, e.Type = colName, tp
+			return e
+		}
+
+		tp = next
+	}
+
+	v := vectorOf(c)
+	t.header = append(t.header[:j], append(Header{colName}, t.header[j:]...)...)
+	t.cols = append(t.cols[:j], append([]vector{v}, t.cols[j:]...)...)
+	return nil
+}
+
+// TryInt returns the (i,j)th value as an integer. An error is returned
+// if the value is not in the table or the column is not of type Int.
+func (t *Table) TryInt(i, j int) (int, error) {
+	if err := t.check(i, j, Int); err != nil {
+		return 0, err
+	}
+
+	return t.cols[j].ints[i], nil
+}
+
+// TryMap mutates each row in a table as Map does. An error is returned
+// if the mutated rows do not fit the table, in which case the table is
+// not changed.
+func (t *Table) TryMap(f Mapper) error {
+	rs := t.Rows()
+	for i := 0; i < len(rs); i++ {
+		f(rs[i])
+	}
+
+	mapped := newTable(t.header, len(rs))
+	if err := mapped.TryAppend(rs...); err != nil {
+		return err
+	}
+
+	t.cols = mapped.cols
+	return nil
+}
+
+// TryRemove removes and returns the ith row from a table as Remove
+// does. An error is returned if the row is out of range.
+func (t *Table) TryRemove(i int) (Row, error) {
+	m, n := t.Dims()
+	if i < 0 || m <= i {
+		return nil, newError(ErrRange, nil, i, -1, nil)
+	}
+
+	r := t.Row(i)
+	for j := 0; j < n; j++ {
+		if 1 < m {
+			t.cols[j].remove(i)
+		} else {
+			t.cols[j] = newVector(Nil, 0)
+		}
+	}
+
+	return r, nil
+}
+
+// TryRemoveCol removes and returns the jth column from a table as
+// RemoveCol does. An error is returned if the column is out of range.
+func (t *Table) TryRemoveCol(j int) (string, Column, error) {
+	if j < 0 || len(t.header) <= j {
+		return "", nil, newError(ErrRange, nil, -1, j, nil)
+	}
+
+	name, column := t.header[j], t.Col(j)
+	t.header = append(t.header[:j], t.header[j+1:]...)
+	t.cols = append(t.cols[:j], t.cols[j+1:]...)
+	return name, column, nil
+}
+
+// TrySet sets the (i,j)th value in a table as Set does. An error is
+// returned if the value is not in the table or does not fit the
+// column.
+func (t *Table) TrySet(i, j int, v interface{}) error {
+	if err := t.check(i, j, Inv); err != nil {
+		return err
+	}
+
+	if unify(t.cols[j].typ, Parse(v)) == Inv {
+		return newError(ErrType, t, i, j, v)
+	}
+
+	t.cols[j].set(i, v)
+	return nil
+}
+
+// TryStr returns the (i,j)th value as a string. An error is returned
+// if the value is not in the table or the column is not of type Str.
+func (t *Table) TryStr(i, j int) (string, error) {
+	if err := t.check(i, j, Str); err != nil {
+		return "", err
+	}
+
+	return t.cols[j].strs[i], nil
+}
+
+// TrySwap swaps two rows in a table as Swap does. An error is returned
+// if either row is out of range.
+func (t *Table) TrySwap(i, j int) error {
+	m, _ := t.Dims()
+	for _, k := range [...]int{i, j} {
+		if k < 0 || m <= k {
+			return newError(ErrRange, nil, k, -1, nil)
+		}
+	}
+
+	for k := 0; k < len(t.cols); k++ {
+		t.cols[k].swap(i, j)
+	}
+
+	return nil
+}
+
+// TrySwapCols swaps two columns in a table as SwapCols does. An error
+// is returned if either column is out of range.
+func (t *Table) TrySwapCols(i, j int) error {
+	for _, k := range [...]int{i, j} {
+		if k < 0 || len(t.header) <= k {
+			return newError(ErrRange, nil, -1, k, nil)
+		}
+	}
+
+	t.header[i], t.header[j] = t.header[j], t.header[i]
+	t.cols[i], t.cols[j] = t.cols[j], t.cols[i]
+	return nil
+}
+
+// TryTime returns the (i,j)th value as a time object. An error is
+// returned if the value is not in the table or the column is not of
+// type Time.
+func (t *Table) TryTime(i, j int) (time.Time, error) {
+	if err := t.check(i, j, Time); err != nil {
+		return time.Time{}, err
+	}
+
+	return t.cols[j].times[i].time, nil
+}
+
+// check returns an error if the (i,j)th value is not in a table or, if
+// the given type is not Inv, the jth column is not of the given type.
+func (t *Table) check(i, j int, tp Type) error {
+	m, n := t.Dims()
+	if i < 0 || m <= i || j < 0 || n <= j {
+		return newError(ErrRange, nil, i, j, nil)
+	}
+
+	if tp != Inv && t.cols[j].typ != tp {
+		e := newError(ErrType, t, i, j, t.cols[j].value(i))
+		e.Type = tp
+		return e
+	}
+
+	return nil
+}
+
+// checkCol returns an error if the jth column is not in a table or is
+// neither of the given type nor of type Nil.
+func (t *Table) checkCol(j int, tp Type) error {
+	if j < 0 || len(t.cols) <= j {
+		return newError(ErrRange, nil, -1, j, nil)
+	}
+
+	if t.cols[j].typ != tp && t.cols[j].typ != Nil {
+		e := newError(ErrType, t, -1, j, nil)
+		e.Type = tp
+		return e
+	}
+
+	return nil
 }
 
 // UnmarshalJSON reads a list of json-encoded bytes into a table.
@@ -941,38 +1120,51 @@ func (t *Table) UnmarshalJSON(b []byte) error {
 // Validate returns an error if a table is in an invalid state.
 func (t *Table) Validate() error {
 	m, n := t.Dims()
-	if len(t.types) != n || len(t.body) != m*n {
-		return errors.New(errDims)
+	if len(t.cols) != n {
+		return ErrDims
 	}
 
-	for i := 0; i < m; i++ {
-		for k, j := i*n, 0; j < n; j++ {
-			if Parse(t.body[k+j]) != t.types[j] {
-				return errors.New(errType)
-			}
+	for j := 0; j < n; j++ {
+		if t.cols[j].len() != m {
+			return ErrDims
+		}
+
+		if !t.cols[j].valid() {
+			return ErrType
 		}
 	}
 
 	return nil
 }
 
+// valid returns a list indicating which values in the jth column are
+// not missing.
+func (t *Table) valid(j int) []bool {
+	valid := make([]bool, 0, t.cols[j].len())
+	for i := 0; i < t.cols[j].len(); i++ {
+		valid = append(valid, !t.cols[j].nulls[i])
+	}
+
+	return valid
+}
+
 // Value returns the (i,j)th value.
 func (t *Table) Value(i, j int) interface{} {
-	return t.body[i*len(t.header)+j]
+	return t.cols[j].value(i)
 }
 
-// WriteCSV writes a table to a csv file.
-func (t *Table) WriteCSV(file string) error {
-	file = filepath.Clean(file)
-	if !strings.EqualFold(filepath.Ext(file), ".csv") {
-		file += ".csv"
+// WriteCSV writes a table to a csv file. If the file exists, it is
+// truncated. Missing values are written as empty fields.
+func (t *Table) WriteCSV(fileName string, opts ...CSVOptions) error {
+	f, err := os.Create(filepath.Clean(fileName))
+	if err != nil {
+		return err
 	}
 
-	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE, os.ModePerm)
-	if err != nil {
+	if err := t.WriteCSVTo(f, opts...); err != nil {
+		f.Close()
 		return err
 	}
 
-	defer f.Close()
-	return csv.NewWriter(f).WriteAll(t.Strings())
+	return f.Close()
 }
@@ -2,11 +2,18 @@ package table
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -141,6 +148,58 @@ func TestFormat(t *testing.T) {
 				"|        4 |    4.4 | true     | 0001-01-01T00:00:00.000000004Z | four    |\n" +
 				"+----------+--------+----------+--------------------------------+---------+\n",
 		},
+		{
+			tbl: New(
+				NewHeader("Größe", "名前", "Emoji"),
+				NewRow(1, "東京", "👩‍💻"),
+				NewRow(22, "e\u0301té", "\x1b[31mred\x1b[0m"),
+			),
+			fmt: Fmt5,
+			exp: "\n" +
+				"+-------+------+-------+\n" +
+				"| Größe | 名前 | Emoji |\n" +
+				"+-------+------+-------+\n" +
+				"|     1 | 東京 | 👩‍💻    |\n" +
+				"|    22 | e\u0301té  | \x1b[31mred\x1b[0m   |\n" +
+				"+-------+------+-------+\n",
+		},
+		{
+			tbl: New(NewHeader("名前"), NewRow("a")),
+			fmt: Format{UpperHoriz: "═", MiddleHoriz: "＝", BottomHoriz: "-="},
+			exp: "\n" +
+				"══════\n" +
+				" 名前 \n" +
+				"＝＝＝\n" +
+				" a    \n" +
+				"-=-=-=\n",
+		},
+		{
+			tbl: New(NewHeader("id"), NewRow(1)),
+			fmt: Format{MiddleHoriz: "＝"},
+			exp: "\n" +
+				" id \n" +
+				"＝＝\n" +
+				"  1 \n",
+		},
+		{
+			tbl: New(NewHeader("abc"), NewRow(1)),
+			fmt: Format{MiddleHoriz: "＝"},
+			exp: "\n" +
+				" abc \n" +
+				"＝＝ \n" +
+				"   1 \n",
+		},
+		{
+			tbl: New(NewHeader("Key", "Note"), NewRow("a\tb", "x"), NewRow("c", "y\x00")),
+			fmt: FmtLight,
+			exp: "\n" +
+				"┌───────────┬──────┐\n" +
+				"│ Key       │ Note │\n" +
+				"├───────────┼──────┤\n" +
+				"│ a       b │ x    │\n" +
+				"│ c         │ y␀   │\n" +
+				"└───────────┴──────┘\n",
+		},
 	}
 
 	for _, test := range tests {
@@ -155,6 +214,261 @@ func TestFormat(t *testing.T) {
 	}
 }
 
+func TestFormatByName(t *testing.T) {
+	tbl := New(
+		NewHeader("id", "name"),
+		NewRow(1, "東京"),
+		NewRow(22, "b"),
+	)
+
+	tests := map[string]string{
+		"ascii": "\n" +
+			"+----+------+\n" +
+			"| id | name |\n" +
+			"+----+------+\n" +
+			"|  1 | 東京 |\n" +
+			"| 22 | b    |\n" +
+			"+----+------+\n",
+		"compact": "\n" +
+			" id  name \n" +
+			"  1  東京 \n" +
+			" 22  b    \n",
+		"double": "\n" +
+			"╔════╦══════╗\n" +
+			"║ id ║ name ║\n" +
+			"╠════╬══════╣\n" +
+			"║  1 ║ 東京 ║\n" +
+			"║ 22 ║ b    ║\n" +
+			"╚════╩══════╝\n",
+		"heavy": "\n" +
+			"┏━━━━┳━━━━━━┓\n" +
+			"┃ id ┃ name ┃\n" +
+			"┣━━━━╋━━━━━━┫\n" +
+			"┃  1 ┃ 東京 ┃\n" +
+			"┃ 22 ┃ b    ┃\n" +
+			"┗━━━━┻━━━━━━┛\n",
+		"light": "\n" +
+			"┌────┬──────┐\n" +
+			"│ id │ name │\n" +
+			"├────┼──────┤\n" +
+			"│  1 │ 東京 │\n" +
+			"│ 22 │ b    │\n" +
+			"└────┴──────┘\n",
+		"org": "\n" +
+			"| id | name |\n" +
+			"|----+------|\n" +
+			"|  1 | 東京 |\n" +
+			"| 22 | b    |\n",
+		"psql": "\n" +
+			" id | name \n" +
+			"----+------\n" +
+			"  1 | 東京 \n" +
+			" 22 | b    \n",
+		"rounded": "\n" +
+			"╭────┬──────╮\n" +
+			"│ id │ name │\n" +
+			"├────┼──────┤\n" +
+			"│  1 │ 東京 │\n" +
+			"│ 22 │ b    │\n" +
+			"╰────┴──────╯\n",
+		"rst-grid": "\n" +
+			"+----+------+\n" +
+			"| id | name |\n" +
+			"+====+======+\n" +
+			"|  1 | 東京 |\n" +
+			"+----+------+\n" +
+			"| 22 | b    |\n" +
+			"+----+------+\n",
+		"rst-simple": "\n" +
+			"==== ======\n" +
+			" id   name \n" +
+			"==== ======\n" +
+			"  1   東京 \n" +
+			" 22   b    \n" +
+			"==== ======\n",
+	}
+
+	if exp, rec := len(tests), len(FormatNames()); exp != rec {
+		t.Fatalf("\nexpected %d formats\nreceived %d\n", exp, rec)
+	}
+
+	for _, name := range FormatNames() {
+		f, err := FormatByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, rec := tests[name], tbl.Format(f); exp != rec {
+			t.Errorf("\n%s\nexpected:\n%q\nreceived:\n%q\n", name, exp, rec)
+		}
+	}
+
+	if f, err := FormatByName("Light"); err != nil {
+		t.Fatal(err)
+	} else if f != FmtLight {
+		t.Fatalf("\nexpected %v\nreceived %v\n", FmtLight, f)
+	}
+
+	var e *Error
+	if _, err := FormatByName("fancy"); !errors.As(err, &e) || e.Err != ErrFormat || e.Name != "fancy" {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrFormat, err)
+	}
+
+	if exp := `format name not found: "fancy"`; exp != e.Error() {
+		t.Fatalf("\nexpected %q\nreceived %q\n", exp, e.Error())
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	tests := []struct {
+		tbl *Table
+		fmt Format
+		opt FormatOptions
+		exp string
+	}{
+		{
+			tbl: New(
+				NewHeader("id", "desc"),
+				NewRow(1, "a long description"),
+				NewRow(22, "x\ny"),
+			),
+			fmt: Fmt5,
+			opt: FormatOptions{
+				Cols: map[string]ColFormat{
+					"id":   {Align: AlignCenter, MinWidth: 4},
+					"desc": {MaxWidth: 8, Wrap: true},
+				},
+			},
+			exp: "\n" +
+				"+------+----------+\n" +
+				"|  id  | desc     |\n" +
+				"+------+----------+\n" +
+				"|  1   | a long   |\n" +
+				"|      | descript |\n" +
+				"|      | ion      |\n" +
+				"|  22  | x        |\n" +
+				"|      | y        |\n" +
+				"+------+----------+\n",
+		},
+		{
+			tbl: New(
+				NewHeader("name", "n"),
+				NewRow("\x1b[31mabcdef\x1b[0m", 1),
+				NewRow("ab", 2),
+			),
+			fmt: Fmt0,
+			opt: FormatOptions{
+				Cols:    map[string]ColFormat{"n": {Align: AlignLeft}},
+				Default: ColFormat{Align: AlignRight, MaxWidth: 4},
+			},
+			exp: "\n" +
+				" name  n \n" +
+				"---------\n" +
+				" \x1b[31mabc…\x1b[0m  1 \n" +
+				"   ab  2 \n",
+		},
+		{
+			tbl: New(NewHeader("name"), NewRow("abcdef")),
+			fmt: Fmt0,
+			opt: FormatOptions{Default: ColFormat{MaxWidth: 5}, Ellipsis: "..."},
+			exp: "\n" +
+				" name  \n" +
+				"-------\n" +
+				" ab... \n",
+		},
+	}
+
+	for _, test := range tests {
+		if rec := test.tbl.Format(test.fmt, test.opt); test.exp != rec {
+			t.Errorf("\nexpected:\n%q\nreceived:\n%q\n", test.exp, rec)
+		}
+	}
+}
+
+func TestWriteFormatTo(t *testing.T) {
+	tbl := New(
+		NewHeader("name", "n"),
+		NewRow("ab", 1.5),
+		NewRow("abcdef", 22.25),
+	)
+
+	var sb strings.Builder
+	if err := tbl.WriteFormatTo(&sb, Fmt5); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, rec := tbl.Format(Fmt5), sb.String(); exp != rec {
+		t.Fatalf("\nexpected:\n%q\nreceived:\n%q\n", exp, rec)
+	}
+
+	tests := []struct {
+		opt FormatOptions
+		exp string
+	}{
+		{
+			opt: FormatOptions{NoLeadingNewline: true, NoTrailingNewline: true},
+			exp: "" +
+				" name        n \n" +
+				"---------------\n" +
+				" ab       1.5  \n" +
+				" abcdef  22.25 ",
+		},
+		{
+			opt: FormatOptions{SampleRows: 1},
+			exp: "\n" +
+				" name    n \n" +
+				"-----------\n" +
+				" ab    1.5 \n" +
+				" abc…  22… \n",
+		},
+	}
+
+	for _, test := range tests {
+		sb.Reset()
+		if err := tbl.WriteFormatTo(&sb, Fmt0, test.opt); err != nil {
+			t.Fatal(err)
+		}
+
+		if rec := sb.String(); test.exp != rec {
+			t.Errorf("\nexpected:\n%q\nreceived:\n%q\n", test.exp, rec)
+		}
+	}
+
+	if err := tbl.WriteFormatTo(failWriter{}, Fmt0); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", io.ErrClosedPipe, err)
+	}
+}
+
+// failWriter fails every write.
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s   string
+		w   int
+		exp []string
+	}{
+		{s: "a b c", w: 5, exp: []string{"a b c"}},
+		{s: "a\tb\u00a0c  d", w: 3, exp: []string{"a b", "c d"}},
+		{s: "abcdef gh", w: 4, exp: []string{"abcd", "ef", "gh"}},
+		{s: "日本\u3000語", w: 4, exp: []string{"日本", "語"}},
+	}
+
+	for _, test := range tests {
+		if rec := wrap(test.s, test.w); strings.Join(rec, "|") != strings.Join(test.exp, "|") {
+			t.Fatalf("\n%q: expected %q\nreceived %q\n", test.s, test.exp, rec)
+		}
+	}
+
+	if rec := lines("a\rb\r\nc\nd"); strings.Join(rec, "|") != "a|b|c|d" {
+		t.Fatalf("\nexpected %q\nreceived %q\n", []string{"a", "b", "c", "d"}, rec)
+	}
+}
+
 func TestAppendCol(t *testing.T) {
 	tests := []struct {
 		tbl, exp *Table
@@ -208,6 +522,93 @@ func TestAppendCol(t *testing.T) {
 	}
 }
 
+func TestByName(t *testing.T) {
+	tbl := New(
+		NewHeader("id", "Name", "score", "name"),
+		NewRow(1, "a", 2.0, "x"),
+		NewRow(2, "b", Null, "y"),
+	)
+
+	if dups := tbl.Header().Duplicates(); len(dups) != 0 {
+		t.Fatalf("\nexpected no duplicates\nreceived %v\n", dups)
+	}
+
+	if j, err := tbl.ColIndex("name"); err != nil || j != 3 {
+		t.Fatalf("\nexpected 3, <nil>\nreceived %d, %v\n", j, err)
+	}
+
+	var e *Error
+	if _, err := tbl.ColIndexFold("NAME"); !errors.As(err, &e) || e.Err != ErrColDup || e.Name != "NAME" {
+		t.Fatalf("\nexpected %q\nreceived %v\n", errColDup, err)
+	}
+
+	if j, err := tbl.ColIndexFold("SCORE"); err != nil || j != 2 {
+		t.Fatalf("\nexpected 2, <nil>\nreceived %d, %v\n", j, err)
+	}
+
+	if _, err := tbl.ColIndex("missing"); !errors.As(err, &e) || e.Err != ErrColName || e.Name != "missing" || e.Col != -1 {
+		t.Fatalf("\nexpected %q\nreceived %v\n", errColName, err)
+	}
+
+	if _, err := tbl.ColByName("missing"); !errors.As(err, &e) || e.Err != ErrColName || e.Name != "missing" {
+		t.Fatalf("\nexpected %q\nreceived %v\n", errColName, err)
+	}
+
+	if _, err := tbl.ColIntsByName("missing"); !errors.As(err, &e) || e.Err != ErrColName || e.Name != "missing" {
+		t.Fatalf("\nexpected %q\nreceived %v\n", errColName, err)
+	}
+
+	if _, err := tbl.ColIntsByName("Name"); !errors.As(err, &e) || e.Err != ErrType || e.Name != "Name" || e.Col != 1 || e.Type != Int || e.Value != Str {
+		t.Fatalf("\nexpected %q\nreceived %v\n", errType, err)
+	}
+
+	if exp := `invalid type: column 1 ("Name"): expected int, received string`; exp != e.Error() {
+		t.Fatalf("\nexpected %q\nreceived %q\n", exp, e.Error())
+	}
+
+	if err := tbl.SetByName(1, "score", "z"); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %q\nreceived %v\n", ErrType, err)
+	}
+
+	if err := tbl.SetByName(1, "score", 3.0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tbl.RenameCol("name", "alias"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tbl.SwapColsByName("id", "alias"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tbl.StableByName(SortKey{Name: "score", Desc: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tbl.SortByName(SortKey{Name: "missing"}); !errors.As(err, &e) || e.Err != ErrColName || e.Name != "missing" {
+		t.Fatalf("\nexpected %q\nreceived %v\n", errColName, err)
+	}
+
+	if _, err := tbl.RemoveColByName("Name"); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := New(
+		NewHeader("alias", "score", "id"),
+		NewRow("y", 3.0, 2),
+		NewRow("x", 2.0, 1),
+	)
+
+	if !exp.Equal(tbl) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, tbl)
+	}
+
+	if v, err := tbl.ValueByName(0, "id"); err != nil || v != 2 {
+		t.Fatalf("\nexpected 2, <nil>\nreceived %v, %v\n", v, err)
+	}
+}
+
 func TestCSV(t *testing.T) {
 	const fileName = "test.csv"
 	var expLines = [][]string{
@@ -364,106 +765,319 @@ func TestCSV(t *testing.T) {
 	closed = true
 }
 
-func TestDims(t *testing.T) {
+func TestCSVReader(t *testing.T) {
 	tests := []struct {
-		tbl        *Table
-		expM, expN int
+		s   string
+		opt CSVOptions
+		exp *Table
 	}{
 		{
-			tbl: New(NewHeader()),
+			s:   "",
+			exp: New(nil),
 		},
 		{
-			tbl: New(
-				NewHeader("Integers", "Floats", "Booleans", "Times", "Strings"),
-				NewRow(0, 0.0, false, NewFTime(time.Time{}.Add(0)), "zero"),
-				NewRow(1, 1.1, false, NewFTime(time.Time{}.Add(1)), "one"),
-				NewRow(2, 2.2, false, NewFTime(time.Time{}.Add(2)), "two"),
-				NewRow(3, 3.3, true, NewFTime(time.Time{}.Add(3)), "three"),
-				NewRow(4, 4.4, true, NewFTime(time.Time{}.Add(4)), "four"),
+			s:   "Integers,Strings\n",
+			exp: New(NewHeader("Integers", "Strings")),
+		},
+		{
+			s: "Integers;Floats;Codes;Times\n" +
+				"# comment\n" +
+				"0;0.0;007;2021-01-01\n" +
+				"1;;;2021-01-02\n",
+			opt: CSVOptions{
+				Comma:    ';',
+				Comment:  '#',
+				Types:    NewTypes(Inv, Flt, Str, Time),
+				TimeFmts: []string{"2006-01-02"},
+			},
+			exp: New(
+				NewHeader("Integers", "Floats", "Codes", "Times"),
+				NewRow(0, 0.0, "007", NewFTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "2006-01-02")),
+				NewRow(1, Null, Null, NewFTime(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), "2006-01-02")),
+			),
+		},
+		{
+			s:   "0,zero\n1,one\n",
+			opt: CSVOptions{NoHeader: true},
+			exp: New(
+				NewHeader("0", "1"),
+				NewRow(0, "zero"),
+				NewRow(1, "one"),
 			),
-			expM: 5,
-			expN: 5,
 		},
 	}
 
 	for _, test := range tests {
-		if recM, recN := test.tbl.Dims(); test.expM != recM || test.expN != recN {
-			t.Errorf("\n"+
-				"expected (%d,%d)\n"+
-				"received (%d,%d)\n",
-				test.expM, test.expN,
-				recM, recN,
-			)
+		rec, err := FromCSVReader(strings.NewReader(test.s), test.opt)
+		if err != nil {
+			t.Fatal(err)
 		}
-	}
-}
-
-func TestFilter(t *testing.T) {
-	{
-		// Evens
-		var (
-			hdr              = NewHeader("n", "even")
-			recGen Generator = func(i, j int) interface{} {
-				switch j {
-				case 0:
-					return i
-				case 1:
-					return i % 2
-				default:
-					return nil
-				}
-			}
-			expGen Generator = func(i, j int) interface{} {
-				switch j {
-				case 0:
-					return i << 1
-				case 1:
-					return (i << 1) % 2
-				default:
-					return nil
-				}
-			}
-			fltr Filterer = func(r Row) bool { return r[0].(int)%2 == 0 }
-			rec           = Generate(hdr, 5, recGen).Filter(fltr)
-			exp           = Generate(hdr, 3, expGen)
-		)
 
-		if !exp.Equal(rec) {
-			t.Fatalf("\n"+
-				"expected %s\n"+
-				"received %s\n",
-				exp,
+		if !test.exp.Equal(rec) {
+			t.Errorf("\n"+
+				"expected:\n%v\n"+
+				"received:\n%v\n",
+				test.exp,
 				rec,
 			)
 		}
 	}
 
-	{
-		// Primes
-		// var (
-		// 	hdr            = NewHeader("n")
-		// 	gen  generator = func(i int) Row { return NewRow(i + 1) }
-		// 	fltr Filterer  = func(r Row) bool {
-		// 		n := r[0].(int)
-		// 		if n == 2 {
-		// 			return true
-		// 		}
-
-		// 		if n&1 == 0 {
-		// 			return false
-		// 		}
+	// Guessed columns are widened.
+	rec, err := FromCSVReader(strings.NewReader("a,b,c\n1,true,2021-03-04T05:06:07Z\n1.5,maybe,later\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
 
-		// 		for i := 3; i < n; i += 2 {
-		// 			if n%i == 0 {
-		// 				return false
-		// 			}
-		// 		}
+	exp := New(NewHeader("a", "b", "c"), NewRow(1.0, "true", "2021-03-04T05:06:07Z"), NewRow(1.5, "maybe", "later"))
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
 
-		// 		return true
-		// 	}
-		// )
+	var e *Error
+	if _, err := FromCSVReader(strings.NewReader("Integers\n0\nzero\n")); !errors.As(err, &e) || !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
 
-		// t.Fatal(gen(hdr, 100, gen).Filter(fltr))
+	if e.Row != 1 || e.Col != 0 || e.Name != "Integers" || e.Type != Int || e.Value != "zero" {
+		t.Fatalf("\nunexpected error %#v\n", e)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	tbl := New(
+		NewHeader("Integers", "Floats", "Strings"),
+		NewRow(0, 0.5, "zero"),
+		NewRow(1, Null, "a, \"b\""),
+	)
+
+	tests := []struct {
+		opt CSVOptions
+		exp string
+	}{
+		{
+			exp: "Integers,Floats,Strings\n" +
+				"0,0.5,zero\n" +
+				"1,,\"a, \"\"b\"\"\"\n",
+		},
+		{
+			opt: CSVOptions{
+				Comma:      ';',
+				QuoteAll:   true,
+				NoHeader:   true,
+				Null:       "NA",
+				Formatters: []Formatter{nil, func(v interface{}) string { return fmt.Sprintf("%.2f", v) }},
+			},
+			exp: "\"0\";\"0.50\";\"zero\"\n" +
+				"\"1\";\"NA\";\"a, \"\"b\"\"\"\n",
+		},
+		{
+			opt: CSVOptions{UseCRLF: true},
+			exp: "Integers,Floats,Strings\r\n" +
+				"0,0.5,zero\r\n" +
+				"1,,\"a, \"\"b\"\"\"\r\n",
+		},
+		{
+			opt: CSVOptions{QuoteAll: true, UseCRLF: true, NoHeader: true},
+			exp: "\"0\",\"0.5\",\"zero\"\r\n" +
+				"\"1\",\"\",\"a, \"\"b\"\"\"\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		var sb strings.Builder
+		if err := tbl.WriteCSVTo(&sb, test.opt); err != nil {
+			t.Fatal(err)
+		}
+
+		if rec := sb.String(); test.exp != rec {
+			t.Errorf("\n"+
+				"expected:\n%q\n"+
+				"received:\n%q\n",
+				test.exp,
+				rec,
+			)
+		}
+	}
+
+	{
+		// Overwriting a longer file truncates it
+		const fileName = "test_truncate.csv"
+		defer os.Remove(fileName)
+
+		if err := tbl.WriteCSV(fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		short := New(NewHeader("Integers"), NewRow(0))
+		if err := short.WriteCSV(fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		rec, err := FromCSV(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !short.Equal(rec) {
+			t.Errorf("\n"+
+				"expected:\n%v\n"+
+				"received:\n%v\n",
+				short,
+				rec,
+			)
+		}
+	}
+
+	{
+		// A missing value in a single column round trips.
+		single := New(NewHeader("x"), NewRow(1), NewRow(Null), NewRow(3))
+		for _, opt := range []CSVOptions{{}, {QuoteAll: true}} {
+			var sb strings.Builder
+			if err := single.WriteCSVTo(&sb, opt); err != nil {
+				t.Fatal(err)
+			}
+
+			if exp := "x\n1\n\"\"\n3\n"; !opt.QuoteAll && exp != sb.String() {
+				t.Fatalf("\nexpected %q\nreceived %q\n", exp, sb.String())
+			}
+
+			rec, err := FromCSVReader(strings.NewReader(sb.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !single.Equal(rec) {
+				t.Fatalf("\nexpected\n%s\nreceived\n%s\n", single, rec)
+			}
+		}
+	}
+}
+
+func TestDims(t *testing.T) {
+	tests := []struct {
+		tbl        *Table
+		expM, expN int
+	}{
+		{
+			tbl: New(NewHeader()),
+		},
+		{
+			tbl: New(
+				NewHeader("Integers", "Floats", "Booleans", "Times", "Strings"),
+				NewRow(0, 0.0, false, NewFTime(time.Time{}.Add(0)), "zero"),
+				NewRow(1, 1.1, false, NewFTime(time.Time{}.Add(1)), "one"),
+				NewRow(2, 2.2, false, NewFTime(time.Time{}.Add(2)), "two"),
+				NewRow(3, 3.3, true, NewFTime(time.Time{}.Add(3)), "three"),
+				NewRow(4, 4.4, true, NewFTime(time.Time{}.Add(4)), "four"),
+			),
+			expM: 5,
+			expN: 5,
+		},
+	}
+
+	for _, test := range tests {
+		if recM, recN := test.tbl.Dims(); test.expM != recM || test.expN != recN {
+			t.Errorf("\n"+
+				"expected (%d,%d)\n"+
+				"received (%d,%d)\n",
+				test.expM, test.expN,
+				recM, recN,
+			)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp int
+	}{
+		{s: "", exp: 0},
+		{s: "abc", exp: 3},
+		{s: "日本語", exp: 6},
+		{s: "e\u0301", exp: 1},
+		{s: "\x1b[1mbold\x1b[0m", exp: 4},
+		{s: "ab\r\nabcd\nabc", exp: 4},
+		{s: "a\tb", exp: 9},
+		{s: "abcdefgh\t", exp: 16},
+		{s: "日本\t\x1b[1mx\x1b[0m", exp: 9},
+		{s: "a\x00b\bc", exp: 5},
+	}
+
+	for _, test := range tests {
+		if rec := DisplayWidth(test.s); test.exp != rec {
+			t.Fatalf("\n%q: expected %d\nreceived %d\n", test.s, test.exp, rec)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	{
+		// Evens
+		var (
+			hdr              = NewHeader("n", "even")
+			recGen Generator = func(i, j int) interface{} {
+				switch j {
+				case 0:
+					return i
+				case 1:
+					return i % 2
+				default:
+					return nil
+				}
+			}
+			expGen Generator = func(i, j int) interface{} {
+				switch j {
+				case 0:
+					return i << 1
+				case 1:
+					return (i << 1) % 2
+				default:
+					return nil
+				}
+			}
+			fltr Filterer = func(r Row) bool { return r[0].(int)%2 == 0 }
+			rec           = Generate(hdr, 5, recGen).Filter(fltr)
+			exp           = Generate(hdr, 3, expGen)
+		)
+
+		if !exp.Equal(rec) {
+			t.Fatalf("\n"+
+				"expected %s\n"+
+				"received %s\n",
+				exp,
+				rec,
+			)
+		}
+	}
+
+	{
+		// Primes
+		// var (
+		// 	hdr            = NewHeader("n")
+		// 	gen  generator = func(i int) Row { return NewRow(i + 1) }
+		// 	fltr Filterer  = func(r Row) bool {
+		// 		n := r[0].(int)
+		// 		if n == 2 {
+		// 			return true
+		// 		}
+
+		// 		if n&1 == 0 {
+		// 			return false
+		// 		}
+
+		// 		for i := 3; i < n; i += 2 {
+		// 			if n%i == 0 {
+		// 				return false
+		// 			}
+		// 		}
+
+		// 		return true
+		// 	}
+		// )
+
+		// t.Fatal(gen(hdr, 100, gen).Filter(fltr))
 	}
 }
 
@@ -529,6 +1143,176 @@ func TestGen(t *testing.T) {
 	}
 }
 
+func TestGroupBy(t *testing.T) {
+	tbl := New(
+		NewHeader("Fruit", "Color", "Count", "Price"),
+		NewRow("apple", "red", 3, 1.5),
+		NewRow("pear", "green", 1, 2.0),
+		NewRow("apple", "green", 2, Null),
+		NewRow("apple", "red", Null, 2.5),
+		NewRow(Null, "blue", 4, 1.0),
+	)
+
+	rec, err := tbl.GroupBy(
+		[]string{"Fruit"},
+		Aggregation{Kind: AggCount},
+		Aggregation{Col: "Count", Kind: AggSum},
+		Aggregation{Col: "Price", Kind: AggMean},
+		Aggregation{Col: "Price", Kind: AggMin},
+		Aggregation{Col: "Price", Kind: AggMax},
+		Aggregation{Col: "Color", Kind: AggFirst},
+		Aggregation{Col: "Color", Kind: AggLast},
+		Aggregation{Col: "Color", Kind: AggDistinct},
+		Aggregation{Col: "Color", Kind: AggConcat, Sep: "|", Name: "Colors"},
+		Aggregation{Col: "Count", Kind: AggFunc, Func: func(c Column) interface{} { return len(c) }},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := New(
+		NewHeader("Fruit", "count", "Count_sum", "Price_mean", "Price_min", "Price_max", "Color_first", "Color_last", "Color_distinct", "Colors", "Count_func"),
+		NewRow("apple", 3, 5, 2.0, 1.5, 2.5, "red", "red", 2, "red|green|red", 3),
+		NewRow("pear", 1, 1, 2.0, 2.0, 2.0, "green", "green", 1, "green", 1),
+		NewRow(Null, 1, 4, 1.0, 1.0, 1.0, "blue", "blue", 1, "blue", 1),
+	)
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\n"+
+			"expected:\n%v\n"+
+			"received:\n%v\n",
+			exp,
+			rec,
+		)
+	}
+
+	{
+		// Result types are derived from the input types
+		rec, err := New(NewHeader("Key", "Value")).GroupBy([]string{"Key"}, Aggregation{Col: "Value", Kind: AggMean})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp := NewTypes(Nil, Flt); !exp.Equal(rec.ColTypes()) {
+			t.Fatalf("\n"+
+				"expected %v\n"+
+				"received %v\n",
+				exp,
+				rec.ColTypes(),
+			)
+		}
+	}
+
+	if _, err := tbl.GroupBy([]string{"Fruit"}, Aggregation{Col: "Color", Kind: AggSum}); err == nil {
+		t.Errorf("expected error summing strings")
+	}
+
+	var e *Error
+	if _, err := tbl.GroupBy([]string{"Weight"}); !errors.As(err, &e) || e.Err != ErrColName || e.Name != "Weight" {
+		t.Errorf("\nexpected %q\nreceived %v\n", errColName, err)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var (
+		tbl = New(
+			NewHeader("id", "<name>"),
+			NewRow(1, "a & b"),
+			NewRow(2, Null),
+		)
+		opts = HTMLOptions{
+			Class: "report",
+			Null:  "-",
+			CellClass: func(i, j int, v interface{}) string {
+				if v == 2 {
+					return "high"
+				}
+
+				return ""
+			},
+		}
+		exp = "" +
+			"<table class=\"report\">\n" +
+			"<thead>\n" +
+			"<tr><th class=\"col-int numeric\">id</th><th class=\"col-string\">&lt;name&gt;</th></tr>\n" +
+			"</thead>\n" +
+			"<tbody>\n" +
+			"<tr><td class=\"col-int numeric\">1</td><td class=\"col-string\">a &amp; b</td></tr>\n" +
+			"<tr><td class=\"col-int numeric high\">2</td><td class=\"col-string\">-</td></tr>\n" +
+			"</tbody>\n" +
+			"</table>\n"
+	)
+
+	if rec := tbl.HTML(opts); exp != rec {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+}
+
+func TestInsertRemove(t *testing.T) {
+	var (
+		tbl = New(
+			NewHeader("Integers", "Strings"),
+			NewRow(0, "zero"),
+			NewRow(2, "two"),
+		)
+		exp = New(
+			NewHeader("Integers", "Floats", "Strings"),
+			NewRow(0, 0.0, "zero"),
+			NewRow(1, 1.1, "one"),
+			NewRow(2, 2.2, "two"),
+		)
+	)
+
+	tbl.Insert(1, NewRow(1, "one")).InsertCol(1, "Floats", NewCol(0.0, 1.1, 2.2))
+	if !exp.Equal(tbl) {
+		t.Fatalf("\n"+
+			"expected %s\n"+
+			"received %s\n",
+			exp,
+			tbl,
+		)
+	}
+
+	if err := tbl.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := tbl.Remove(1); !r.Equal(NewRow(1, 1.1, "one")) {
+		t.Fatalf("\n"+
+			"expected %v\n"+
+			"received %v\n",
+			NewRow(1, 1.1, "one"),
+			r,
+		)
+	}
+
+	if name, c := tbl.RemoveCol(1); name != "Floats" || !c.Equal(NewCol(0.0, 2.2)) {
+		t.Fatalf("\n"+
+			"expected Floats %v\n"+
+			"received %s %v\n",
+			NewCol(0.0, 2.2),
+			name,
+			c,
+		)
+	}
+
+	exp = New(
+		NewHeader("Integers", "Strings"),
+		NewRow(0, "zero"),
+		NewRow(2, "two"),
+	)
+
+	if !exp.Equal(tbl) {
+		t.Fatalf("\n"+
+			"expected %s\n"+
+			"received %s\n",
+			exp,
+			tbl,
+		)
+	}
+}
+
 func TestJoin(t *testing.T) {
 	tests := []struct {
 		tbl []*Table
@@ -666,14 +1450,151 @@ func TestJoin(t *testing.T) {
 	}
 }
 
-func TestJSON(t *testing.T) {
-	expJSON, err := sjson.Set("", "header", []string{"Integers", "Floats", "Booleans", "Times", "Strings"})
-	if err != nil {
-		t.Fatal(err)
-	}
+func TestJoinOn(t *testing.T) {
+	var (
+		left = New(
+			NewHeader("ID", "Name", "Score"),
+			NewRow(1, "one", 1.0),
+			NewRow(2, "two", 2.0),
+			NewRow(Null, "none", 0.0),
+			NewRow(3, "three", 3.0),
+		)
+		right = New(
+			NewHeader("ID", "Score"),
+			NewRow(2, 20.0),
+			NewRow(3, 30.0),
+			NewRow(3, 33.0),
+			NewRow(4, 40.0),
+		)
+		h = NewHeader("ID", "Name", "Score_left", "Score_right")
+	)
 
-	expJSON, err = sjson.Set(expJSON, "types", []int{1, 2, 3, 4, 5})
-	if err != nil {
+	tests := []struct {
+		kind JoinKind
+		exp  *Table
+	}{
+		{
+			kind: InnerJoin,
+			exp: New(h,
+				NewRow(2, "two", 2.0, 20.0),
+				NewRow(3, "three", 3.0, 30.0),
+				NewRow(3, "three", 3.0, 33.0),
+			),
+		},
+		{
+			kind: LeftJoin,
+			exp: New(h,
+				NewRow(1, "one", 1.0, Null),
+				NewRow(2, "two", 2.0, 20.0),
+				NewRow(Null, "none", 0.0, Null),
+				NewRow(3, "three", 3.0, 30.0),
+				NewRow(3, "three", 3.0, 33.0),
+			),
+		},
+		{
+			kind: RightJoin,
+			exp: New(h,
+				NewRow(2, "two", 2.0, 20.0),
+				NewRow(3, "three", 3.0, 30.0),
+				NewRow(3, "three", 3.0, 33.0),
+				NewRow(4, Null, Null, 40.0),
+			),
+		},
+		{
+			kind: FullJoin,
+			exp: New(h,
+				NewRow(1, "one", 1.0, Null),
+				NewRow(2, "two", 2.0, 20.0),
+				NewRow(Null, "none", 0.0, Null),
+				NewRow(3, "three", 3.0, 30.0),
+				NewRow(3, "three", 3.0, 33.0),
+				NewRow(4, Null, Null, 40.0),
+			),
+		},
+		{
+			kind: SemiJoin,
+			exp: New(
+				NewHeader("ID", "Name", "Score"),
+				NewRow(2, "two", 2.0),
+				NewRow(3, "three", 3.0),
+			),
+		},
+		{
+			kind: AntiJoin,
+			exp: New(
+				NewHeader("ID", "Name", "Score"),
+				NewRow(1, "one", 1.0),
+				NewRow(Null, "none", 0.0),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		rec, err := JoinOn(left, right, test.kind, []string{"ID"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !test.exp.Equal(rec) {
+			t.Errorf("\n"+
+				"   given: kind = %d\n"+
+				"expected:\n%v\n"+
+				"received:\n%v\n",
+				test.kind,
+				test.exp,
+				rec,
+			)
+		}
+	}
+
+	{
+		// Differently named keys and custom suffixes
+		other := New(
+			NewHeader("Key", "Score"),
+			NewRow(1, 10.0),
+		)
+
+		exp := New(
+			NewHeader("ID", "Name", "Score_a", "Score_b"),
+			NewRow(1, "one", 1.0, 10.0),
+		)
+
+		rec, err := JoinOn(left, other, InnerJoin, []string{"ID"}, JoinOptions{RightOn: []string{"Key"}, LeftSuffix: "_a", RightSuffix: "_b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !exp.Equal(rec) {
+			t.Errorf("\n"+
+				"expected:\n%v\n"+
+				"received:\n%v\n",
+				exp,
+				rec,
+			)
+		}
+	}
+
+	if _, err := JoinOn(left, right, InnerJoin, []string{"Name"}); err == nil {
+		t.Errorf("expected error joining on a missing column")
+	}
+
+	if _, err := JoinOn(left, right, InnerJoin, []string{"Score"}); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := JoinOn(left, New(NewHeader("ID"), NewRow("1")), InnerJoin, []string{"ID"}); err == nil {
+		t.Errorf("expected error joining on mismatched types")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	expJSON, err := sjson.Set("", "header", []string{"Integers", "Floats", "Booleans", "Times", "Strings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expJSON, err = sjson.Set(expJSON, "types", []int{1, 2, 3, 4, 5})
+	if err != nil {
 		t.Fatal(err)
 	}
 
@@ -767,6 +1688,146 @@ func TestJSON(t *testing.T) {
 			)
 		}
 	}
+
+	{
+		// Escaping and round trips
+		tbl := New(
+			NewHeader(`a"b`, "c\\d", "e\nf"),
+			NewRow("x\"y\\z\n\t\x01", math.NaN(), 1),
+			NewRow(Null, math.Inf(1), Null),
+			NewRow("</script>", math.Inf(-1), 3),
+		)
+
+		for _, s := range []string{tbl.JSON(), tbl.JSONIndent("", "  ")} {
+			if !json.Valid([]byte(s)) {
+				t.Fatalf("\ninvalid json\n%s\n", s)
+			}
+
+			rec, err := FromJSON(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !rec.Header().Equal(tbl.Header()) || !rec.ColTypes().Equal(tbl.ColTypes()) {
+				t.Fatalf("\nexpected\n%s\nreceived\n%s\n", tbl, rec)
+			}
+
+			if !NewCol(rec.Col(0)...).Equal(tbl.Col(0)) || !NewCol(rec.Col(2)...).Equal(tbl.Col(2)) {
+				t.Fatalf("\nexpected\n%s\nreceived\n%s\n", tbl, rec)
+			}
+
+			if fs := rec.ColFloats(1); !math.IsNaN(fs[0]) || !math.IsInf(fs[1], 1) || !math.IsInf(fs[2], -1) {
+				t.Fatalf("\nexpected [NaN +Inf -Inf]\nreceived %v\n", fs)
+			}
+		}
+
+		if s := tbl.JSONIndent("", "  "); !strings.Contains(s, "\n  \"header\": [\n") {
+			t.Fatalf("\nexpected indented json\nreceived\n%s\n", s)
+		}
+	}
+}
+
+func TestJSONRecords(t *testing.T) {
+	var (
+		s   = `[{"id":1,"name":"a","when":"2021-03-04T05:06:07Z"},{"score":2.5,"id":2,"name":"b\"c"},{"id":3,"score":3,"when":null}]`
+		ft  = NewFTime(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+		exp = New(
+			NewHeader("id", "name", "when", "score"),
+			NewRow(1, "a", ft, Null),
+			NewRow(2, "b\"c", Null, 2.5),
+			NewRow(3, Null, Null, 3.0),
+		)
+	)
+
+	rec, err := FromJSONRecords(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	// Round trip
+	if rec, err = FromJSONRecords(exp.JSONRecords()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	var v []map[string]interface{}
+	if err := json.Unmarshal([]byte(exp.JSONRecords()), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	// Floats that are not finite and escaping
+	special := New(
+		NewHeader("<x>", "f", "s"),
+		NewRow(1, math.NaN(), "a&b"),
+		NewRow(1, math.Inf(1), "NaN"),
+		NewRow(Null, math.Inf(-1), Null),
+	)
+
+	expJSON := `[{"\u003cx\u003e":1,"f":"NaN","s":"a\u0026b"},{"\u003cx\u003e":1,"f":"Infinity","s":"NaN"},{"\u003cx\u003e":null,"f":"-Infinity","s":null}]`
+	if recJSON := special.JSONRecords(); expJSON != recJSON {
+		t.Fatalf("\nexpected %s\nreceived %s\n", expJSON, recJSON)
+	}
+
+	for _, s := range []string{special.JSONRecords(), special.NDJSON()} {
+		var rec *Table
+		if strings.HasPrefix(s, "[") {
+			rec, err = FromJSONRecords(s)
+		} else {
+			rec, err = FromNDJSONReader(strings.NewReader(s))
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !rec.ColTypes().Equal(special.ColTypes()) || !NewCol(rec.Col(2)...).Equal(special.Col(2)) {
+			t.Fatalf("\nexpected\n%s\nreceived\n%s\n", special, rec)
+		}
+
+		if fs := rec.ColFloats(1); !math.IsNaN(fs[0]) || !math.IsInf(fs[1], 1) || !math.IsInf(fs[2], -1) {
+			t.Fatalf("\nexpected [NaN +Inf -Inf]\nreceived %v\n", fs)
+		}
+	}
+
+	// Explicit header
+	rec, err = FromJSONRecords(s, JSONOptions{Header: NewHeader("name", "id")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := New(NewHeader("name", "id"), NewRow("a", 1), NewRow("b\"c", 2), NewRow(Null, 3)); !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	var e *Error
+	if _, err := FromJSONRecords(`[{"a":1},{"a":"b"}]`); !errors.As(err, &e) || !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if e.Row != 1 || e.Col != 0 || e.Name != "a" || e.Type != Int || e.Value != "b" {
+		t.Fatalf("\nunexpected error %#v\n", e)
+	}
+
+	for s, exp := range map[string]string{
+		`[{"a":1.5},{"a":true}]`: "expected float64, received bool",
+		`[{"a":1},{"a":[2]}]`:    "expected int, received []interface {}",
+		`[{"a":1},{"a":{}}]`:     "expected int, received map[string]interface {}",
+	} {
+		if _, err := FromJSONRecords(s); err == nil || !strings.HasSuffix(err.Error(), exp) {
+			t.Fatalf("\nexpected %q\nreceived %v\n", exp, err)
+		}
+	}
+
+	if _, err := FromJSONRecords(`{"a":1}`); !errors.Is(err, ErrJSON) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrJSON, err)
+	}
 }
 
 func TestMap(t *testing.T) {
@@ -810,6 +1871,363 @@ func TestMap(t *testing.T) {
 	}
 }
 
+func TestNumFormat(t *testing.T) {
+	tests := []struct {
+		nf  NumFormat
+		v   interface{}
+		exp string
+	}{
+		{nf: NumFormat{}, v: 1234567, exp: "1234567"},
+		{nf: NumFormat{}, v: 2.0, exp: "2.0"},
+		{nf: NumFormat{}, v: math.NaN(), exp: "NaN"},
+		{nf: NumFormat{}, v: "a", exp: "a"},
+		{nf: NumFormat{Group: true}, v: 1234567, exp: "1,234,567"},
+		{nf: NumFormat{Group: true, Sep: "."}, v: -1234567, exp: "-1.234.567"},
+		{nf: NumFormat{Kind: NumFixed, Prec: 2, Group: true}, v: 1234.5, exp: "1,234.50"},
+		{nf: NumFormat{Kind: NumFixed, Prec: 2, Group: true, Sep: "."}, v: 1234.5, exp: "1.234,50"},
+		{nf: NumFormat{}, v: 1e20, exp: "100000000000000000000.0"},
+		{nf: NumFormat{Kind: NumFixed, Prec: 2, Group: true, Prefix: "$"}, v: 3, exp: "$3.00"},
+		{nf: NumFormat{Kind: NumFixed, Prec: 1, Group: true}, v: -1234.56, exp: "-1,234.6"},
+		{nf: NumFormat{Kind: NumSig, Prec: 3}, v: 123456, exp: "123000"},
+		{nf: NumFormat{Kind: NumSig, Prec: 3}, v: 0.0012345, exp: "0.00123"},
+		{nf: NumFormat{Kind: NumSig, Prec: 3}, v: 999.6, exp: "1000"},
+		{nf: NumFormat{Kind: NumSig, Prec: 3, Group: true}, v: 1234567.0, exp: "1,230,000"},
+		{nf: NumFormat{Kind: NumPercent, Prec: 1}, v: 0.1234, exp: "12.3%"},
+		{nf: NumFormat{Kind: NumSci, Prec: 2}, v: 12345.0, exp: "1.23e+04"},
+		{nf: NumFormat{Kind: NumSI, Prec: 1}, v: 1234, exp: "1.2k"},
+		{nf: NumFormat{Kind: NumSI, Prec: 1, Suffix: "s"}, v: 0.000015, exp: "15.0µs"},
+		{nf: NumFormat{Kind: NumSI, Prec: 1}, v: 999960.0, exp: "1.0M"},
+		{nf: NumFormat{Kind: NumSI}, v: 0, exp: "0"},
+	}
+
+	for _, test := range tests {
+		if rec := test.nf.Formatter()(test.v); test.exp != rec {
+			t.Errorf("\n%+v: %v\nexpected %q\nreceived %q\n", test.nf, test.v, test.exp, rec)
+		}
+	}
+
+	var (
+		tbl = New(
+			NewHeader("price", "cost"),
+			NewRow(1.5, 1234.5),
+			NewRow(22.25, 3.0),
+			NewRow(3.0, Null),
+		)
+		money = NumFormat{Kind: NumFixed, Prec: 2, Group: true, Prefix: "$"}.Formatter()
+	)
+
+	{
+		// Format with decimal points aligned
+		exp := "\n" +
+			" price       cost \n" +
+			"------------------\n" +
+			"  1.5   $1,234.50 \n" +
+			" 22.25      $3.00 \n" +
+			"  3.0             \n"
+
+		if rec := tbl.Format(Fmt0, FormatOptions{Cols: map[string]ColFormat{"cost": {Formatter: money}}}); exp != rec {
+			t.Fatalf("\nexpected:\n%q\nreceived:\n%q\n", exp, rec)
+		}
+	}
+
+	{
+		// Values without digits are not aligned
+		exp := "\n" +
+			"     x \n" +
+			"-------\n" +
+			"  1.5  \n" +
+			" 22.25 \n" +
+			"   NaN \n" +
+			"  +Inf \n"
+
+		if rec := New(NewHeader("x"), NewRow(1.5), NewRow(22.25), NewRow(math.NaN()), NewRow(math.Inf(1))).Format(Fmt0); exp != rec {
+			t.Fatalf("\nexpected:\n%q\nreceived:\n%q\n", exp, rec)
+		}
+	}
+
+	{
+		// Decimal points marked by ","
+		exp := "\n" +
+			"        x \n" +
+			"----------\n" +
+			" 1.234,5  \n" +
+			"     3,25 \n" +
+			"    22,0  \n"
+
+		var (
+			euro = NumFormat{Group: true, Sep: "."}.Formatter()
+			rec  = New(NewHeader("x"), NewRow(1234.5), NewRow(3.25), NewRow(22.0)).Format(Fmt0, FormatOptions{Default: ColFormat{Formatter: euro, Point: ","}})
+		)
+
+		if exp != rec {
+			t.Fatalf("\nexpected:\n%q\nreceived:\n%q\n", exp, rec)
+		}
+	}
+
+	{
+		// CSV
+		exp := "price,cost\n1.5,\"$1,234.50\"\n22.25,$3.00\n3.0,\n"
+
+		var sb strings.Builder
+		if err := tbl.WriteCSVTo(&sb, CSVOptions{Formatters: []Formatter{nil, money}}); err != nil {
+			t.Fatal(err)
+		}
+
+		if rec := sb.String(); exp != rec {
+			t.Fatalf("\nexpected:\n%q\nreceived:\n%q\n", exp, rec)
+		}
+	}
+
+	{
+		// Markdown
+		exp := "" +
+			"| price |      cost |\n" +
+			"| ----: | --------: |\n" +
+			"|   1.5 | $1,234.50 |\n" +
+			"| 22.25 |     $3.00 |\n" +
+			"|   3.0 |           |\n"
+
+		if rec := tbl.Markdown(MarkdownOptions{Formatters: []Formatter{nil, money}}); exp != rec {
+			t.Fatalf("\nexpected:\n%q\nreceived:\n%q\n", exp, rec)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var (
+		ft  = NewFTime(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), "2006-01-02")
+		tbl = New(
+			NewHeader("id", "name", "score", "date"),
+			NewRow(1, "a|b", 1.5, ft),
+			NewRow(10, "c\nd", Null, Null),
+		)
+		exp = "" +
+			"|  id | name   | score | date       |\n" +
+			"| --: | ------ | ----: | ---------- |\n" +
+			"|   1 | a\\|b   |   1.5 | 2021-03-04 |\n" +
+			"|  10 | c<br>d |       |            |\n"
+	)
+
+	if rec := tbl.Markdown(); exp != rec {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	var (
+		s = "" +
+			`{"id":1,"when":"2021-03-04T05:06:07Z"}` + "\n" +
+			"\n" +
+			`{"id":2,"score":1}` + "\n" +
+			`{"id":3,"score":1.5,"when":"soon"}` + "\n" +
+			`{"id":4}`
+		exp = New(
+			NewHeader("id", "when", "score"),
+			NewRow(1, "2021-03-04T05:06:07Z", Null),
+			NewRow(2, Null, 1.0),
+			NewRow(3, "soon", 1.5),
+			NewRow(4, Null, Null),
+		)
+	)
+
+	rec, err := FromNDJSONReader(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	// Batches
+	var (
+		nr      = NewNDJSONReader(strings.NewReader(s))
+		batches []int
+	)
+
+	for {
+		batch := New(nil)
+		n, err := nr.ReadInto(batch, 3)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if m, _ := batch.Dims(); m != n {
+			t.Fatalf("\nexpected %d rows\nreceived %d\n", n, m)
+		}
+
+		batches = append(batches, n)
+	}
+
+	if len(batches) != 2 || batches[0] != 3 || batches[1] != 1 {
+		t.Fatalf("\nexpected [3 1]\nreceived %v\n", batches)
+	}
+
+	// Round trip
+	var buf bytes.Buffer
+	if err := exp.WriteNDJSONTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(buf.String(), "\n") != 4 {
+		t.Fatalf("\nexpected 4 lines\nreceived\n%s\n", buf.String())
+	}
+
+	if rec, err = FromNDJSONReader(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	if _, err := FromNDJSONReader(strings.NewReader(`{"id":1}` + "\n" + `{"id":"a"}`)); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if _, err := FromNDJSONReader(strings.NewReader(`[1]`)); !errors.Is(err, ErrJSON) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrJSON, err)
+	}
+
+	// A line that does not fit leaves the table unchanged.
+	var (
+		partial = New(NewHeader("id", "name"), NewRow(1, "a"))
+		pr      = NewNDJSONReader(strings.NewReader(`{"id":1.5,"extra":true,"name":2}` + "\n" + `{"id":2,"name":"b"}`))
+		e       *Error
+	)
+
+	if _, err := pr.ReadInto(partial, 1); !errors.As(err, &e) || !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if e.Row != 1 || e.Col != 1 || e.Name != "name" || e.Type != Str || e.Value != 2 {
+		t.Fatalf("\nunexpected error %#v\n", e)
+	}
+
+	if exp := "expected string, received int"; !strings.HasSuffix(e.Error(), exp) {
+		t.Fatalf("\nexpected %q\nreceived %v\n", exp, e)
+	}
+
+	if _, err := pr.ReadInto(partial, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	exp = New(NewHeader("id", "name"), NewRow(1, "a"), NewRow(2, "b"))
+	if !exp.Equal(partial) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, partial)
+	}
+
+	if tp := partial.ColType(0); tp != Int {
+		t.Fatalf("\nexpected %v\nreceived %v\n", Int, tp)
+	}
+}
+
+func TestNull(t *testing.T) {
+	tbl := New(
+		NewHeader("Integers", "Floats", "Strings", "Missing"),
+		NewRow(Null, 0.0, "zero", Null),
+		NewRow(1, Null, "one", Null),
+		NewRow(2, 2.2, Null, Null),
+	)
+
+	if err := tbl.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, rec := NewTypes(Int, Flt, Str, Nil), tbl.ColTypes(); !exp.Equal(rec) {
+		t.Fatalf("\n"+
+			"expected %v\n"+
+			"received %v\n",
+			exp,
+			rec,
+		)
+	}
+
+	{
+		// Json round trip
+		rec, err := FromJSON(tbl.JSON())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !tbl.Equal(rec) {
+			t.Fatalf("\n"+
+				"expected %s\n"+
+				"received %s\n",
+				tbl,
+				rec,
+			)
+		}
+	}
+
+	{
+		// Format placeholder
+		f := Fmt0
+		f.Null = "-"
+
+		exp := "\n" +
+			" Integers  Floats  Strings  Missing \n" +
+			"------------------------------------\n" +
+			"        -     0.0  zero     -       \n" +
+			"        1       -  one      -       \n" +
+			"        2     2.2  -        -       \n"
+
+		if rec := tbl.Format(f); exp != rec {
+			t.Fatalf("\n"+
+				"expected:\n%q\n"+
+				"received:\n%q\n",
+				exp,
+				rec,
+			)
+		}
+	}
+
+	{
+		// Missing values are sorted last
+		exp := New(
+			NewHeader("Integers", "Floats", "Strings", "Missing"),
+			NewRow(1, Null, "one", Null),
+			NewRow(2, 2.2, Null, Null),
+			NewRow(Null, 0.0, "zero", Null),
+		)
+
+		if rec := tbl.Copy().Stable(0); !exp.Equal(rec) {
+			t.Fatalf("\n"+
+				"expected %s\n"+
+				"received %s\n",
+				exp,
+				rec,
+			)
+		}
+	}
+
+	{
+		// Nullable column accessors
+		c, valid := tbl.ColNullInts(0)
+		if exp := []int{0, 1, 2}; fmt.Sprint(exp) != fmt.Sprint(c) {
+			t.Fatalf("\n"+
+				"expected %v\n"+
+				"received %v\n",
+				exp,
+				c,
+			)
+		}
+
+		if exp := []bool{false, true, true}; fmt.Sprint(exp) != fmt.Sprint(valid) {
+			t.Fatalf("\n"+
+				"expected %v\n"+
+				"received %v\n",
+				exp,
+				valid,
+			)
+		}
+	}
+}
+
 func TestReduce(t *testing.T) {
 	{
 		// Sum 0 + 1 + ... + (n-1) = n*(n-1)/2
@@ -833,6 +2251,250 @@ func TestReduce(t *testing.T) {
 	}
 }
 
+func TestSortBy(t *testing.T) {
+	tbl := New(
+		NewHeader("Group", "Name", "Score"),
+		NewRow(1, "b", 2.0),
+		NewRow(0, "C", Null),
+		NewRow(1, "a", 2.0),
+		NewRow(0, "a", 1.0),
+		NewRow(1, "B", 3.0),
+	)
+
+	tests := []struct {
+		keys   []SortKey
+		stable bool
+		exp    *Table
+	}{
+		{
+			keys:   []SortKey{{Col: 0}, {Col: 2, Desc: true}},
+			stable: true,
+			exp: New(
+				NewHeader("Group", "Name", "Score"),
+				NewRow(0, "a", 1.0),
+				NewRow(0, "C", Null),
+				NewRow(1, "B", 3.0),
+				NewRow(1, "b", 2.0),
+				NewRow(1, "a", 2.0),
+			),
+		},
+		{
+			keys:   []SortKey{{Col: 2, NullsFirst: true}, {Col: 1}},
+			stable: true,
+			exp: New(
+				NewHeader("Group", "Name", "Score"),
+				NewRow(0, "C", Null),
+				NewRow(0, "a", 1.0),
+				NewRow(1, "a", 2.0),
+				NewRow(1, "b", 2.0),
+				NewRow(1, "B", 3.0),
+			),
+		},
+		{
+			keys:   []SortKey{{Name: "Score", NullsFirst: true}, {Col: 0, Name: "Name"}},
+			stable: true,
+			exp: New(
+				NewHeader("Group", "Name", "Score"),
+				NewRow(0, "C", Null),
+				NewRow(0, "a", 1.0),
+				NewRow(1, "a", 2.0),
+				NewRow(1, "b", 2.0),
+				NewRow(1, "B", 3.0),
+			),
+		},
+		{
+			keys: []SortKey{{Col: 1, Fold: true, Desc: true}, {Col: 0}},
+			exp: New(
+				NewHeader("Group", "Name", "Score"),
+				NewRow(0, "C", Null),
+				NewRow(1, "b", 2.0),
+				NewRow(1, "B", 3.0),
+				NewRow(0, "a", 1.0),
+				NewRow(1, "a", 2.0),
+			),
+		},
+		{
+			keys: []SortKey{
+				{
+					Col: 1,
+					Compare: func(a, b interface{}) int {
+						// Reverse order
+						return strings.Compare(b.(string), a.(string))
+					},
+				},
+			},
+			exp: New(
+				NewHeader("Group", "Name", "Score"),
+				NewRow(1, "b", 2.0),
+				NewRow(1, "a", 2.0),
+				NewRow(0, "a", 1.0),
+				NewRow(0, "C", Null),
+				NewRow(1, "B", 3.0),
+			),
+			stable: true,
+		},
+	}
+
+	for i, test := range tests {
+		var rec *Table
+		if test.stable {
+			rec = tbl.Copy().StableBy(test.keys...)
+		} else {
+			rec = tbl.Copy().SortBy(test.keys...)
+		}
+
+		if !test.exp.Equal(rec) {
+			t.Errorf("\n"+
+				"    test: %d\n"+
+				"expected:\n%v\n"+
+				"received:\n%v\n",
+				i,
+				test.exp,
+				rec,
+			)
+		}
+	}
+
+	// NaN follows every number and precedes missing values.
+	nans := New(NewHeader("x"))
+	for i := 0; i < 40; i++ {
+		switch {
+		case i%5 == 0:
+			nans.Append(NewRow(math.NaN()))
+		case i%7 == 0:
+			nans.Append(NewRow(Null))
+		default:
+			nans.Append(NewRow(float64((i * 7) % 19)))
+		}
+	}
+
+	nans.SortBy(SortKey{Col: 0})
+	var (
+		fs, valid = nans.ColNullFloats(0)
+		seen      int // 0: numbers, 1: NaN, 2: missing values
+	)
+
+	for i := 0; i < len(fs); i++ {
+		state := 0
+		switch {
+		case !valid[i]:
+			state = 2
+		case math.IsNaN(fs[i]):
+			state = 1
+		case 0 < i && seen == 0 && fs[i] < fs[i-1]:
+			t.Fatalf("\nexpected sorted floats\nreceived %v\n", fs)
+		}
+
+		if state < seen {
+			t.Fatalf("\nexpected numbers, NaN, then missing values\nreceived %v\n", nans.Col(0))
+		}
+
+		seen = state
+	}
+
+	defer func() {
+		var e *Error
+		if err, _ := recover().(error); !errors.As(err, &e) || e.Err != ErrColName || e.Name != "Missing" {
+			t.Fatalf("\nexpected %v\nreceived %v\n", ErrColName, err)
+		}
+	}()
+
+	tbl.SortBy(SortKey{Name: "Missing"})
+}
+
+func TestSQLRows(t *testing.T) {
+	when := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	db, _ := openFakeDB(t, map[string]fakeRows{
+		"SELECT * FROM t": {
+			cols:  []string{"id", "name", "score", "created", "active", "notes"},
+			types: []string{"BIGINT", "VARCHAR(16)", "", "TIMESTAMP", "BOOLEAN", "TEXT"},
+			rows: [][]driver.Value{
+				{int64(1), []byte("a"), nil, when, int64(1), nil},
+				{int64(2), nil, 1.5, "2021-03-04T05:06:07Z", false, nil},
+			},
+		},
+	})
+
+	rows, err := db.Query("SELECT * FROM t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+	rec, err := FromSQLRows(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := New(
+		NewHeader("id", "name", "score", "created", "active", "notes"),
+		NewRow(1, "a", Null, NewFTime(when), true, Null),
+		NewRow(2, Null, 1.5, NewFTime(when), false, Null),
+	)
+
+	// The notes column is typed by the database, so compare rows
+	if m, _ := rec.Dims(); m != 2 || !exp.Row(0).Equal(rec.Row(0)) || !exp.Row(1).Equal(rec.Row(1)) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	if exp := NewTypes(Int, Str, Flt, Time, Bool, Str); !exp.Equal(rec.ColTypes()) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", exp, rec.ColTypes())
+	}
+
+	db, _ = openFakeDB(t, map[string]fakeRows{
+		"SELECT * FROM d": {
+			cols:  []string{"day", "at"},
+			types: []string{"DATE", "DATETIME"},
+			rows: [][]driver.Value{
+				{[]byte("2021-03-04"), []byte("2021-03-04 05:06:07")},
+				{"2021-03-05", "2021-03-04 05:06:07.123456"},
+			},
+		},
+	})
+
+	if rows, err = db.Query("SELECT * FROM d"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+	if rec, err = FromSQLRows(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := NewTypes(Time, Time); !exp.Equal(rec.ColTypes()) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", exp, rec.ColTypes())
+	}
+
+	for i, exp := range [][]time.Time{
+		{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), when},
+		{time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC), when.Add(123456 * time.Microsecond)},
+	} {
+		for j := range exp {
+			if rec := rec.Time(i, j); !exp[j].Equal(rec) {
+				t.Fatalf("\nexpected %v\nreceived %v\n", exp[j], rec)
+			}
+		}
+	}
+
+	db, _ = openFakeDB(t, map[string]fakeRows{
+		"SELECT": {
+			cols:  []string{"id"},
+			types: []string{"INTEGER"},
+			rows:  [][]driver.Value{{"one"}},
+		},
+	})
+
+	if rows, err = db.Query("SELECT"); err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+	var e *Error
+	if _, err := FromSQLRows(rows); !errors.As(err, &e) || e.Err != ErrType || e.Row != 0 || e.Name != "id" {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+}
+
 func TestStable(t *testing.T) {
 	tests := []struct {
 		tbl, exp *Table
@@ -949,6 +2611,406 @@ func TestStable(t *testing.T) {
 	}
 }
 
+func TestStructs(t *testing.T) {
+	type (
+		Base struct {
+			ID uint16 `table:"id"`
+		}
+
+		Record struct {
+			Base
+			Name   string `table:"name"`
+			Score  *float32
+			When   time.Time `table:"when"`
+			Active bool
+			Notes  []string `table:"-"`
+			hidden int
+		}
+	)
+
+	var (
+		score = float32(1.1)
+		when  = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+		rs    = []*Record{
+			{Base: Base{ID: 1}, Name: "a", Score: &score, When: when, Active: true},
+			nil,
+			{Base: Base{ID: 2}, Name: "b", When: when},
+		}
+		exp = New(
+			NewHeader("id", "name", "Score", "when", "Active"),
+			NewRow(1, "a", 1.1, NewFTime(when), true),
+			NewRow(Null, Null, Null, Null, Null),
+			NewRow(2, "b", Null, NewFTime(when), false),
+		)
+	)
+
+	rec, err := FromStructs(rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	if rec, err = FromStructs([]Record(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := NewTypes(Int, Str, Flt, Time, Bool); !exp.Equal(rec.ColTypes()) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", exp, rec.ColTypes())
+	}
+
+	type Unsupported struct {
+		Tags map[string]string
+	}
+
+	var e *Error
+	if _, err := FromStructs([]Unsupported{{}}); !errors.As(err, &e) || e.Err != ErrField || e.Name != "Tags" {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrField, err)
+	}
+
+	if _, err := FromStructs(1); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	{
+		// Decode into structs
+		type Decoded struct {
+			ID    int8    `table:"id"`
+			Name  *string `table:"name"`
+			Score float64
+			When  FTime `table:"when"`
+			Extra string
+		}
+
+		var ds []Decoded
+		if err := exp.ToStructs(&ds); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ds) != 3 || ds[0].ID != 1 || *ds[0].Name != "a" || ds[1].Name != nil || ds[2].Score != 0 || !ds[2].When.Equal(NewFTime(when)) {
+			t.Fatalf("\nunexpected structs %+v\n", ds)
+		}
+
+		// Round trip
+		var rs2 []*Record
+		if err := exp.ToStructs(&rs2); err != nil {
+			t.Fatal(err)
+		}
+
+		if rs2[0].ID != 1 || *rs2[0].Score != score || !rs2[0].When.Equal(when) || !rs2[0].Active {
+			t.Fatalf("\nunexpected struct %+v\n", rs2[0])
+		}
+
+		var mismatched []struct {
+			Name int `table:"name"`
+		}
+
+		if err := exp.ToStructs(&mismatched); !errors.As(err, &e) || e.Err != ErrType || e.Row != 0 || e.Col != 1 || e.Type != Int {
+			t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+		}
+
+		var small []struct {
+			ID int8 `table:"id"`
+		}
+
+		if err := New(NewHeader("id"), NewRow(300)).ToStructs(&small); !errors.Is(err, ErrRange) {
+			t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+		}
+	}
+
+	{
+		// Embedded pointers and promoted names
+		type (
+			Meta struct {
+				Source string
+				Label  string `table:"Label"`
+				Note   string
+			}
+
+			Extra struct {
+				Label string
+				Note  string
+				Count int
+			}
+
+			Outer struct {
+				*Meta
+				Extra
+				Source string
+			}
+		)
+
+		var (
+			outers = []Outer{
+				{Meta: &Meta{Source: "x", Label: "a", Note: "y"}, Extra: Extra{Label: "z", Count: 1}, Source: "s"},
+				{Extra: Extra{Count: 2}, Source: "t"},
+			}
+			exp = New(
+				NewHeader("Label", "Count", "Source"),
+				NewRow("a", 1, "s"),
+				NewRow(Null, 2, "t"),
+			)
+		)
+
+		rec, err := FromStructs(outers)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !exp.Equal(rec) {
+			t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+		}
+
+		var dec []Outer
+		if err := exp.ToStructs(&dec); err != nil {
+			t.Fatal(err)
+		}
+
+		if dec[0].Meta == nil || dec[0].Meta.Label != "a" || dec[0].Count != 1 || dec[0].Source != "s" || dec[1].Meta != nil || dec[1].Count != 2 {
+			t.Fatalf("\nunexpected structs %+v\n", dec)
+		}
+	}
+}
+
+func TestTry(t *testing.T) {
+	tbl := New(NewHeader("id", "name"), NewRow(1, "a"))
+
+	err := tbl.TryAppend(NewRow(2, "b"), NewRow("3", "c"))
+	var e *Error
+	if !errors.As(err, &e) || !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if e.Row != 2 || e.Col != 0 || e.Name != "id" || e.Type != Int || e.Value != "3" {
+		t.Fatalf("\nunexpected error %#v\n", e)
+	}
+
+	if m, _ := tbl.Dims(); m != 1 {
+		t.Fatalf("\nexpected 1 row\nreceived %d\n", m)
+	}
+
+	if err := tbl.TryAppend(NewRow(2)); !errors.Is(err, ErrDims) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrDims, err)
+	}
+
+	if err := tbl.TryAppendCol("score", NewCol(1.0, 2.0)); !errors.Is(err, ErrDims) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrDims, err)
+	}
+
+	if _, err := TryNewCol(1, Null, "a"); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if err := tbl.TrySet(1, 0, 2); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	if err := tbl.TrySet(0, 1, 2); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if _, err := tbl.TryStr(0, 0); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if n, err := tbl.TryInt(0, 0); err != nil || n != 1 {
+		t.Fatalf("\nexpected 1, <nil>\nreceived %d, %v\n", n, err)
+	}
+
+	if err := tbl.TryMap(func(r Row) { r[1] = []byte("b") }); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if s := tbl.Str(0, 1); s != "a" {
+		t.Fatalf("\nexpected %q\nreceived %q\n", "a", s)
+	}
+
+	if _, err := TryGenerate(NewHeader("x"), 2, func(i, j int) interface{} { return int64(i) }); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if err := tbl.TryInsert(2, NewRow(2, "b")); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	err = tbl.TryInsert(0, NewRow(2, 3))
+	if !errors.As(err, &e) || !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if e.Row != 0 || e.Col != 1 || e.Name != "name" || e.Type != Str || e.Value != 3 {
+		t.Fatalf("\nunexpected error %#v\n", e)
+	}
+
+	if err := tbl.TryInsert(0, NewRow(0, "z")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tbl.TryInsertCol(3, "score", NewCol(1.0, 2.0)); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	if err := tbl.TryInsertCol(0, "score", NewCol(1.0)); !errors.Is(err, ErrDims) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrDims, err)
+	}
+
+	if err := tbl.TryInsertCol(0, "score", Column{1.0, "a"}); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if err := tbl.TrySwap(0, 2); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	if err := tbl.TrySwapCols(-1, 0); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	if _, err := tbl.TryRemove(2); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	if _, _, err := tbl.TryRemoveCol(2); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	if _, err := tbl.TryColInts(2); !errors.Is(err, ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrRange, err)
+	}
+
+	if _, err := tbl.TryColInts(1); !errors.Is(err, ErrType) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", ErrType, err)
+	}
+
+	if ns, err := tbl.TryColInts(0); err != nil || len(ns) != 2 || ns[0] != 0 || ns[1] != 1 {
+		t.Fatalf("\nexpected [0 1], <nil>\nreceived %v, %v\n", ns, err)
+	}
+
+	exp := New(NewHeader("id", "name"), NewRow(0, "z"), NewRow(1, "a"))
+	if !exp.Equal(tbl) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, tbl)
+	}
+}
+
+func TestWriteSQL(t *testing.T) {
+	var (
+		when = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+		tbl  = New(
+			NewHeader("id", "na\"me", "score", "when", "ok"),
+			NewRow(1, "it's", 1.5, NewFTime(when), true),
+			NewRow(2, Null, math.NaN(), Null, false),
+			NewRow(3, `a\b`, 2.0, NewFTime(when), Null),
+		)
+		buf bytes.Buffer
+	)
+
+	if err := tbl.WriteSQLTo(&buf, "scores", SQLOptions{Dialect: PostgreSQL, BatchSize: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := "" +
+		"CREATE TABLE \"scores\" (\n" +
+		"\t\"id\" BIGINT,\n" +
+		"\t\"na\"\"me\" TEXT,\n" +
+		"\t\"score\" DOUBLE PRECISION,\n" +
+		"\t\"when\" TIMESTAMPTZ,\n" +
+		"\t\"ok\" BOOLEAN\n" +
+		");\n" +
+		"INSERT INTO \"scores\" (\"id\", \"na\"\"me\", \"score\", \"when\", \"ok\") VALUES (1, 'it''s', 1.5, '2021-03-04T05:06:07Z', TRUE),\n" +
+		"\t(2, NULL, NULL, NULL, FALSE);\n" +
+		"INSERT INTO \"scores\" (\"id\", \"na\"\"me\", \"score\", \"when\", \"ok\") VALUES (3, 'a\\b', 2, '2021-03-04T05:06:07Z', NULL);\n"
+
+	if rec := buf.String(); exp != rec {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	buf.Reset()
+	if err := tbl.WriteSQLTo(&buf, "scores", SQLOptions{Dialect: MySQL, NoCreate: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp = "" +
+		"INSERT INTO `scores` (`id`, `na\"me`, `score`, `when`, `ok`) VALUES (1, 'it''s', 1.5, '2021-03-04 05:06:07', TRUE);\n" +
+		"INSERT INTO `scores` (`id`, `na\"me`, `score`, `when`, `ok`) VALUES (2, NULL, NULL, NULL, FALSE);\n" +
+		"INSERT INTO `scores` (`id`, `na\"me`, `score`, `when`, `ok`) VALUES (3, 'a\\\\b', 2, '2021-03-04 05:06:07', NULL);\n"
+
+	if rec := buf.String(); exp != rec {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	db, fdb := openFakeDB(t, nil)
+	if err := tbl.WriteSQL(context.Background(), db, "scores"); err != nil {
+		t.Fatal(err)
+	}
+
+	insert := `INSERT INTO "scores" ("id", "na""me", "score", "when", "ok") VALUES (?, ?, ?, ?, ?)`
+	expExecs := []string{
+		"BEGIN",
+		tbl.CreateTableSQL("scores") + "[]",
+		insert + fmt.Sprint([]driver.Value{int64(1), "it's", 1.5, when, true}),
+		insert + fmt.Sprint([]driver.Value{int64(2), nil, nil, nil, false}),
+		insert + fmt.Sprint([]driver.Value{int64(3), `a\b`, 2.0, when, nil}),
+		"COMMIT",
+	}
+
+	if len(expExecs) != len(fdb.execs) {
+		t.Fatalf("\nexpected %q\nreceived %q\n", expExecs, fdb.execs)
+	}
+
+	for i := 0; i < len(expExecs); i++ {
+		if expExecs[i] != fdb.execs[i] {
+			t.Fatalf("\nexpected %q\nreceived %q\n", expExecs[i], fdb.execs[i])
+		}
+	}
+
+	// Batches
+	db, fdb = openFakeDB(t, nil)
+	if err := tbl.WriteSQL(context.Background(), db, "scores", SQLOptions{Dialect: PostgreSQL, NoCreate: true, BatchSize: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	insert = `INSERT INTO "scores" ("id", "na""me", "score", "when", "ok") VALUES `
+	expExecs = []string{
+		"BEGIN",
+		insert + "($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)" + fmt.Sprint([]driver.Value{int64(1), "it's", 1.5, when, true, int64(2), nil, nil, nil, false}),
+		insert + "($1, $2, $3, $4, $5)" + fmt.Sprint([]driver.Value{int64(3), `a\b`, 2.0, when, nil}),
+		"COMMIT",
+	}
+
+	if len(expExecs) != len(fdb.execs) {
+		t.Fatalf("\nexpected %q\nreceived %q\n", expExecs, fdb.execs)
+	}
+
+	for i := 0; i < len(expExecs); i++ {
+		if expExecs[i] != fdb.execs[i] {
+			t.Fatalf("\nexpected %q\nreceived %q\n", expExecs[i], fdb.execs[i])
+		}
+	}
+
+	// Batches are limited by the number of arguments a statement takes
+	wide := New(NewHeader("a", "b"))
+	for i := 0; i < 20000; i++ {
+		wide.Append(NewRow(i, i))
+	}
+
+	db, fdb = openFakeDB(t, nil)
+	if err := wide.WriteSQL(context.Background(), db, "wide", SQLOptions{NoCreate: true, BatchSize: 20000}); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := 4; exp != len(fdb.execs) {
+		t.Fatalf("\nexpected %d\nreceived %d\n", exp, len(fdb.execs))
+	}
+
+	for i, exp := range []int{16383, 3617} {
+		if rec := strings.Count(fdb.execs[i+1], "(?, ?)"); exp != rec {
+			t.Fatalf("\nexpected %d\nreceived %d\n", exp, rec)
+		}
+	}
+}
+
 // ------------------------------------------------------------------------------------
 // Benchmarks
 // ------------------------------------------------------------------------------------
@@ -996,3 +3058,200 @@ func benchmarkJoin(b *testing.B, name string, joinFn func(tbl ...*Table) *Table,
 
 	return b.Run(name, f)
 }
+
+// benchmarkDims are the dimensions of tall and wide tables used in
+// benchmarks.
+var benchmarkDims = []struct{ m, n int }{
+	{m: 1 << 14, n: 4},  // Tall
+	{m: 16, n: 1 << 12}, // Wide
+	{m: 256, n: 256},    // Square
+}
+
+// benchmarkTable returns an m-by-n table of integers.
+func benchmarkTable(m, n int) *Table {
+	return Generate(make(Header, n), m, func(i, j int) interface{} { return i*n + j })
+}
+
+func BenchmarkAppendRemoveCol(b *testing.B) {
+	for _, d := range benchmarkDims {
+		var (
+			tbl = benchmarkTable(d.m, d.n)
+			col = tbl.Col(0)
+		)
+
+		b.Run(fmt.Sprintf("AppendCol RemoveCol %dx%d table", d.m, d.n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tbl.AppendCol("", col).RemoveCol(d.n)
+			}
+		})
+	}
+}
+
+func BenchmarkColInts(b *testing.B) {
+	for _, d := range benchmarkDims {
+		tbl := benchmarkTable(d.m, d.n)
+		b.Run(fmt.Sprintf("ColInts %dx%d table", d.m, d.n), func(b *testing.B) {
+			var c []int
+			for i := 0; i < b.N; i++ {
+				c = tbl.ColInts(d.n >> 1)
+			}
+			_ = c
+		})
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for _, d := range benchmarkDims {
+		b.Run(fmt.Sprintf("Generate %dx%d table", d.m, d.n), func(b *testing.B) {
+			var tbl *Table
+			for i := 0; i < b.N; i++ {
+				tbl = benchmarkTable(d.m, d.n)
+			}
+			_ = tbl
+		})
+	}
+}
+
+func BenchmarkStable(b *testing.B) {
+	for _, d := range benchmarkDims {
+		tbl := Generate(make(Header, d.n), d.m, func(i, j int) interface{} { return (i * 7919) % d.m })
+		b.Run(fmt.Sprintf("Stable %dx%d table", d.m, d.n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tbl.Copy().Stable(0)
+			}
+		})
+	}
+}
+
+func BenchmarkStrings(b *testing.B) {
+	for _, d := range benchmarkDims {
+		tbl := benchmarkTable(d.m, d.n)
+		b.Run(fmt.Sprintf("Strings %dx%d table", d.m, d.n), func(b *testing.B) {
+			var ss [][]string
+			for i := 0; i < b.N; i++ {
+				ss = tbl.Strings()
+			}
+			_ = ss
+		})
+	}
+}
+
+// ------------------------------------------------------------------------------------
+// Fake database/sql driver
+// ------------------------------------------------------------------------------------
+
+func init() {
+	sql.Register("table_fake", fakeDriver{})
+}
+
+// fakeDBs holds each fake database by name.
+var fakeDBs = struct {
+	sync.Mutex
+	m map[string]*fakeDB
+}{m: make(map[string]*fakeDB)}
+
+type (
+	// fakeDB returns a fixed result for each query and records each
+	// executed statement.
+	fakeDB struct {
+		sync.Mutex
+		results map[string]fakeRows
+		execs   []string
+	}
+
+	fakeDriver struct{}
+	fakeConn   struct{ db *fakeDB }
+	fakeStmt   struct {
+		db    *fakeDB
+		query string
+	}
+	fakeTx   struct{ db *fakeDB }
+	fakeRows struct {
+		cols  []string
+		types []string
+		rows  [][]driver.Value
+	}
+)
+
+// openFakeDB returns a new database holding the given query results.
+func openFakeDB(t *testing.T, results map[string]fakeRows) (*sql.DB, *fakeDB) {
+	fdb := &fakeDB{results: results}
+	fakeDBs.Lock()
+	fakeDBs.m[t.Name()] = fdb
+	fakeDBs.Unlock()
+
+	db, err := sql.Open("table_fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db, fdb
+}
+
+func (fdb *fakeDB) record(s string) {
+	fdb.Lock()
+	fdb.execs = append(fdb.execs, s)
+	fdb.Unlock()
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBs.Lock()
+	defer fakeDBs.Unlock()
+	return &fakeConn{db: fakeDBs.m[name]}, nil
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return &fakeTx{db: c.db}, nil
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.db.record("COMMIT")
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.record("ROLLBACK")
+	return nil
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.record(fmt.Sprint(s.query, args))
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rs, ok := s.db.results[s.query]
+	if !ok {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+
+	return &rs, nil
+}
+
+func (rs *fakeRows) Columns() []string { return rs.cols }
+func (rs *fakeRows) Close() error      { return nil }
+
+func (rs *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	return rs.types[index]
+}
+
+func (rs *fakeRows) Next(dest []driver.Value) error {
+	if len(rs.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, rs.rows[0])
+	rs.rows = rs.rows[1:]
+	return nil
+}
@@ -20,8 +20,21 @@ const (
 
 	// Str corresponds to type string.
 	Str
+
+	// Nil corresponds to a missing value. A column of type Nil holds
+	// only missing values.
+	Nil
 )
 
+// Null is a missing value. It may be held in a column of any type.
+var Null interface{} = null{}
+
+// null is the underlying type of Null.
+type null struct{}
+
+// typeNames are the names of each type.
+var typeNames = [...]string{"invalid", "int", "float64", "bool", "time", "string", "null"}
+
 // Type corresponds to a basic type.
 type Type byte
 
@@ -67,6 +80,38 @@ func Parse(x interface{}) Type {
 		return Time
 	case string:
 		return Str
+	case null:
+		return Nil
+	default:
+		return Inv
+	}
+}
+
+// IsNull determines if a value is missing.
+func IsNull(x interface{}) bool {
+	return x == Null
+}
+
+// String returns the name of a type.
+func (t Type) String() string {
+	if int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+
+	return typeNames[Inv]
+}
+
+// unify returns the type of a column of type c after a value of type v
+// is stored in it. Inv is returned if the value may not be stored in
+// the column.
+func unify(c, v Type) Type {
+	switch {
+	case v == Inv:
+		return Inv
+	case v == Nil || v == c:
+		return c
+	case c == Nil:
+		return v
 	default:
 		return Inv
 	}
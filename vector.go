@@ -0,0 +1,548 @@
+package table
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vector is a list of values of a single type. Only the list
+// corresponding to the vector's type is used. Missing values are
+// stored as the zero value and are marked in the list of nulls, which
+// always holds one entry per value.
+type vector struct {
+	typ   Type
+	ints  []int
+	flts  []float64
+	bools []bool
+	times []FTime
+	strs  []string
+	nulls []bool
+}
+
+// newVector returns a new, empty vector of a given type with the
+// capacity to hold m values.
+func newVector(typ Type, m int) vector {
+	v := vector{typ: typ, nulls: make([]bool, 0, m)}
+	switch typ {
+	case Int:
+		v.ints = make([]int, 0, m)
+	case Flt:
+		v.flts = make([]float64, 0, m)
+	case Bool:
+		v.bools = make([]bool, 0, m)
+	case Time:
+		v.times = make([]FTime, 0, m)
+	case Str:
+		v.strs = make([]string, 0, m)
+	case Nil:
+	default:
+		panic(errType)
+	}
+
+	return v
+}
+
+// vectorOf returns a new vector holding the values of a column.
+func vectorOf(c Column) vector {
+	typ := c.Type()
+	if typ == Inv {
+		panic(errType)
+	}
+
+	v := newVector(typ, len(c))
+	for i := 0; i < len(c); i++ {
+		v.append(c[i])
+	}
+
+	return v
+}
+
+// append a value to a vector. The value's type is assumed to unify
+// with the vector's type.
+func (v *vector) append(x interface{}) {
+	if x == Null {
+		v.nulls = append(v.nulls, true)
+		switch v.typ {
+		case Int:
+			v.ints = append(v.ints, 0)
+		case Flt:
+			v.flts = append(v.flts, 0)
+		case Bool:
+			v.bools = append(v.bools, false)
+		case Time:
+			v.times = append(v.times, FTime{})
+		case Str:
+			v.strs = append(v.strs, "")
+		default:
+		}
+
+		return
+	}
+
+	if v.typ == Nil {
+		v.retype(Parse(x))
+	}
+
+	v.nulls = append(v.nulls, false)
+	switch v.typ {
+	case Int:
+		v.ints = append(v.ints, x.(int))
+	case Flt:
+		v.flts = append(v.flts, x.(float64))
+	case Bool:
+		v.bools = append(v.bools, x.(bool))
+	case Time:
+		v.times = append(v.times, x.(FTime))
+	case Str:
+		v.strs = append(v.strs, x.(string))
+	default:
+		panic(errType)
+	}
+}
+
+// compare returns -1, 0, or 1 if the ith value is less than, equal
+// to, or greater than the kth value. Missing values are greater than
+// all other values and NaN is greater than all other floats.
+func (v *vector) compare(i, k int) int {
+	switch {
+	case v.nulls[i] && v.nulls[k]:
+		return 0
+	case v.nulls[i]:
+		return 1
+	case v.nulls[k]:
+		return -1
+	}
+
+	switch v.typ {
+	case Int:
+		switch {
+		case v.ints[i] < v.ints[k]:
+			return -1
+		case v.ints[k] < v.ints[i]:
+			return 1
+		}
+	case Flt:
+		switch iNaN, kNaN := math.IsNaN(v.flts[i]), math.IsNaN(v.flts[k]); {
+		case iNaN && kNaN:
+		case iNaN:
+			return 1
+		case kNaN:
+			return -1
+		case v.flts[i] < v.flts[k]:
+			return -1
+		case v.flts[k] < v.flts[i]:
+			return 1
+		}
+	case Bool:
+		switch {
+		case !v.bools[i] && v.bools[k]:
+			return -1
+		case v.bools[i] && !v.bools[k]:
+			return 1
+		}
+	case Time:
+		return v.times[i].Compare(v.times[k])
+	case Str:
+		return strings.Compare(v.strs[i], v.strs[k])
+	default:
+		panic(errType)
+	}
+
+	return 0
+}
+
+// copy returns a copy of a vector.
+func (v *vector) copy() vector {
+	cpy := vector{typ: v.typ, nulls: append(make([]bool, 0, len(v.nulls)), v.nulls...)}
+	switch v.typ {
+	case Int:
+		cpy.ints = append(make([]int, 0, len(v.ints)), v.ints...)
+	case Flt:
+		cpy.flts = append(make([]float64, 0, len(v.flts)), v.flts...)
+	case Bool:
+		cpy.bools = append(make([]bool, 0, len(v.bools)), v.bools...)
+	case Time:
+		cpy.times = append(make([]FTime, 0, len(v.times)), v.times...)
+	case Str:
+		cpy.strs = append(make([]string, 0, len(v.strs)), v.strs...)
+	default:
+	}
+
+	return cpy
+}
+
+// equal determines if two vectors are equal.
+func (v *vector) equal(u *vector) bool {
+	if v.typ != u.typ || len(v.nulls) != len(u.nulls) {
+		return false
+	}
+
+	for i := 0; i < len(v.nulls); i++ {
+		if v.nulls[i] != u.nulls[i] {
+			return false
+		}
+
+		switch v.typ {
+		case Int:
+			if v.ints[i] != u.ints[i] {
+				return false
+			}
+		case Flt:
+			if v.flts[i] != u.flts[i] {
+				return false
+			}
+		case Bool:
+			if v.bools[i] != u.bools[i] {
+				return false
+			}
+		case Time:
+			if v.times[i] != u.times[i] {
+				return false
+			}
+		case Str:
+			if v.strs[i] != u.strs[i] {
+				return false
+			}
+		default:
+		}
+	}
+
+	return true
+}
+
+// key returns a string uniquely identifying the ith value. Equal
+// values have equal keys. Time stamps are identified by their instant
+// regardless of format.
+func (v *vector) key(i int) string {
+	if v.nulls[i] {
+		return "-"
+	}
+
+	var s string
+	switch v.typ {
+	case Int:
+		s = strconv.Itoa(v.ints[i])
+	case Flt:
+		if f := v.flts[i]; f == 0 {
+			s = "0" // -0 is equal to 0
+		} else {
+			s = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	case Bool:
+		s = strconv.FormatBool(v.bools[i])
+	case Time:
+		s = v.times[i].time.UTC().Format(time.RFC3339Nano)
+	case Str:
+		s = v.strs[i]
+	default:
+	}
+
+	return strconv.Itoa(len(s)) + ":" + s
+}
+
+// len returns the number of values in a vector.
+func (v *vector) len() int {
+	return len(v.nulls)
+}
+
+// move sets the ith value to the kth value.
+func (v *vector) move(i, k int) {
+	v.nulls[i] = v.nulls[k]
+	switch v.typ {
+	case Int:
+		v.ints[i] = v.ints[k]
+	case Flt:
+		v.flts[i] = v.flts[k]
+	case Bool:
+		v.bools[i] = v.bools[k]
+	case Time:
+		v.times[i] = v.times[k]
+	case Str:
+		v.strs[i] = v.strs[k]
+	default:
+	}
+}
+
+// permute returns a new vector in which the ith value is the p[i]th
+// value of the vector.
+func (v *vector) permute(p []int) vector {
+	u := newVector(v.typ, len(p))
+	u.nulls = u.nulls[:len(p)]
+	for i := 0; i < len(p); i++ {
+		u.nulls[i] = v.nulls[p[i]]
+	}
+
+	switch v.typ {
+	case Int:
+		u.ints = u.ints[:len(p)]
+		for i := 0; i < len(p); i++ {
+			u.ints[i] = v.ints[p[i]]
+		}
+	case Flt:
+		u.flts = u.flts[:len(p)]
+		for i := 0; i < len(p); i++ {
+			u.flts[i] = v.flts[p[i]]
+		}
+	case Bool:
+		u.bools = u.bools[:len(p)]
+		for i := 0; i < len(p); i++ {
+			u.bools[i] = v.bools[p[i]]
+		}
+	case Time:
+		u.times = u.times[:len(p)]
+		for i := 0; i < len(p); i++ {
+			u.times[i] = v.times[p[i]]
+		}
+	case Str:
+		u.strs = u.strs[:len(p)]
+		for i := 0; i < len(p); i++ {
+			u.strs[i] = v.strs[p[i]]
+		}
+	default:
+	}
+
+	return u
+}
+
+// remove the ith value from a vector.
+func (v *vector) remove(i int) {
+	v.nulls = append(v.nulls[:i], v.nulls[i+1:]...)
+	switch v.typ {
+	case Int:
+		v.ints = append(v.ints[:i], v.ints[i+1:]...)
+	case Flt:
+		v.flts = append(v.flts[:i], v.flts[i+1:]...)
+	case Bool:
+		v.bools = append(v.bools[:i], v.bools[i+1:]...)
+	case Time:
+		v.times = append(v.times[:i], v.times[i+1:]...)
+	case Str:
+		v.strs = append(v.strs[:i], v.strs[i+1:]...)
+	default:
+	}
+}
+
+// retype sets the type of a vector holding only missing values.
+func (v *vector) retype(typ Type) {
+	if v.typ != Nil {
+		panic(errType)
+	}
+
+	m := len(v.nulls)
+	*v = newVector(typ, cap(v.nulls))
+	v.nulls = v.nulls[:m]
+	for i := 0; i < m; i++ {
+		v.nulls[i] = true
+	}
+
+	switch typ {
+	case Int:
+		v.ints = v.ints[:m]
+	case Flt:
+		v.flts = v.flts[:m]
+	case Bool:
+		v.bools = v.bools[:m]
+	case Time:
+		v.times = v.times[:m]
+	case Str:
+		v.strs = v.strs[:m]
+	default:
+	}
+}
+
+// set the ith value. The value's type is assumed to unify with the
+// vector's type.
+func (v *vector) set(i int, x interface{}) {
+	if x == Null {
+		v.nulls[i] = true
+		switch v.typ {
+		case Int:
+			v.ints[i] = 0
+		case Flt:
+			v.flts[i] = 0
+		case Bool:
+			v.bools[i] = false
+		case Time:
+			v.times[i] = FTime{}
+		case Str:
+			v.strs[i] = ""
+		default:
+		}
+
+		return
+	}
+
+	if v.typ == Nil {
+		v.retype(Parse(x))
+	}
+
+	v.nulls[i] = false
+	switch v.typ {
+	case Int:
+		v.ints[i] = x.(int)
+	case Flt:
+		v.flts[i] = x.(float64)
+	case Bool:
+		v.bools[i] = x.(bool)
+	case Time:
+		v.times[i] = x.(FTime)
+	case Str:
+		v.strs[i] = x.(string)
+	default:
+		panic(errType)
+	}
+}
+
+// str returns the ith value converted to a string. Missing values are
+// converted to the empty string.
+func (v *vector) str(i int) string {
+	if v.nulls[i] {
+		return ""
+	}
+
+	switch v.typ {
+	case Int:
+		return strconv.Itoa(v.ints[i])
+	case Flt:
+		if f := v.flts[i]; f == float64(int(f)) {
+			return strconv.FormatFloat(f, 'f', 1, 64) // Forces f.0 when value is an integer
+		}
+
+		return strconv.FormatFloat(v.flts[i], 'f', -1, 64)
+	case Bool:
+		return strconv.FormatBool(v.bools[i])
+	case Time:
+		return v.times[i].String()
+	case Str:
+		return v.strs[i]
+	default:
+		panic(errType)
+	}
+}
+
+// swap the ith and kth values.
+func (v *vector) swap(i, k int) {
+	v.nulls[i], v.nulls[k] = v.nulls[k], v.nulls[i]
+	switch v.typ {
+	case Int:
+		v.ints[i], v.ints[k] = v.ints[k], v.ints[i]
+	case Flt:
+		v.flts[i], v.flts[k] = v.flts[k], v.flts[i]
+	case Bool:
+		v.bools[i], v.bools[k] = v.bools[k], v.bools[i]
+	case Time:
+		v.times[i], v.times[k] = v.times[k], v.times[i]
+	case Str:
+		v.strs[i], v.strs[k] = v.strs[k], v.strs[i]
+	default:
+	}
+}
+
+// truncate a vector to its first m values.
+func (v *vector) truncate(m int) {
+	v.nulls = v.nulls[:m]
+	switch v.typ {
+	case Int:
+		v.ints = v.ints[:m]
+	case Flt:
+		v.flts = v.flts[:m]
+	case Bool:
+		v.bools = v.bools[:m]
+	case Time:
+		v.times = v.times[:m]
+	case Str:
+		v.strs = v.strs[:m]
+	default:
+	}
+}
+
+// valid determines if a vector is in a valid state.
+func (v *vector) valid() bool {
+	m := len(v.nulls)
+	switch v.typ {
+	case Int:
+		return len(v.ints) == m
+	case Flt:
+		return len(v.flts) == m
+	case Bool:
+		return len(v.bools) == m
+	case Time:
+		return len(v.times) == m
+	case Str:
+		return len(v.strs) == m
+	case Nil:
+		for i := 0; i < m; i++ {
+			if !v.nulls[i] {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// widen converts a vector of integers to floats or a vector of
+// booleans or time stamps to strings.
+func (v *vector) widen(typ Type) {
+	switch {
+	case v.typ == Int && typ == Flt:
+		v.flts = make([]float64, 0, cap(v.ints))
+		for i := 0; i < len(v.ints); i++ {
+			v.flts = append(v.flts, float64(v.ints[i]))
+		}
+
+		v.ints = nil
+	case v.typ == Bool && typ == Str:
+		v.strs = make([]string, 0, cap(v.bools))
+		for i := 0; i < len(v.bools); i++ {
+			if v.nulls[i] {
+				v.strs = append(v.strs, "")
+			} else {
+				v.strs = append(v.strs, strconv.FormatBool(v.bools[i]))
+			}
+		}
+
+		v.bools = nil
+	case v.typ == Time && typ == Str:
+		v.strs = make([]string, 0, cap(v.times))
+		for i := 0; i < len(v.times); i++ {
+			if v.nulls[i] {
+				v.strs = append(v.strs, "")
+			} else {
+				v.strs = append(v.strs, v.times[i].String())
+			}
+		}
+
+		v.times = nil
+	default:
+		panic(errType)
+	}
+
+	v.typ = typ
+}
+
+// value returns the ith value.
+func (v *vector) value(i int) interface{} {
+	if v.nulls[i] {
+		return Null
+	}
+
+	switch v.typ {
+	case Int:
+		return v.ints[i]
+	case Flt:
+		return v.flts[i]
+	case Bool:
+		return v.bools[i]
+	case Time:
+		return v.times[i]
+	case Str:
+		return v.strs[i]
+	default:
+		panic(errType)
+	}
+}
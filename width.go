@@ -0,0 +1,332 @@
+package table
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// tabWidth is the number of cells between tab stops.
+const tabWidth = 8
+
+// widthCond measures display widths. East Asian ambiguous characters
+// are narrow regardless of the locale.
+var widthCond = &runewidth.Condition{StrictEmojiNeutral: true}
+
+// segment is either a grapheme cluster or an ANSI escape sequence, which
+// occupies no cells.
+type segment struct {
+	s   string
+	w   int
+	esc bool
+}
+
+// DisplayWidth returns the number of terminal cells occupied by the
+// widest line of a string, measured as Format measures values. Wide
+// characters occupy two cells; combining marks and ANSI escape
+// sequences occupy none. Tabs reach the next of the stops set every
+// eight cells and other control characters occupy one cell each.
+func DisplayWidth(s string) int {
+	var w int
+	for _, line := range lines(s) {
+		if lw := width(line); w < lw {
+			w = lw
+		}
+	}
+
+	return w
+}
+
+// width returns the number of terminal cells a string occupies when
+// displayed. Wide characters occupy two cells; combining marks and
+// characters joined into a single grapheme by zero-width joiners occupy
+// none. ANSI escape sequences are ignored.
+func width(s string) int {
+	return widthCond.StringWidth(stripANSI(s))
+}
+
+// expandControls returns a line with each tab expanded to the spaces
+// reaching the next tab stop and each other control character replaced
+// by its control picture, such as ␀ for NUL, so the line occupies
+// the cells it is measured to. ANSI escape sequences are kept.
+func expandControls(s string) string {
+	if strings.IndexFunc(s, isControl) < 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for 0 < len(s) {
+		if k := ansiLen(s); 0 < k {
+			sb.WriteString(s[:k])
+			s = s[k:]
+			continue
+		}
+
+		k := strings.IndexFunc(s, isControl)
+		if k < 0 {
+			sb.WriteString(s)
+			break
+		}
+
+		sb.WriteString(s[:k])
+		switch c := s[k]; c {
+		case '\x1b':
+			s = s[k:]
+			continue
+		case '\t':
+			sb.WriteString(strings.Repeat(" ", tabWidth-width(sb.String())%tabWidth))
+		case '\x7f':
+			sb.WriteRune('␡')
+		default:
+			sb.WriteRune(0x2400 + rune(c))
+		}
+
+		s = s[k+1:]
+	}
+
+	return sb.String()
+}
+
+// isControl determines if a rune is a C0 control character or DEL.
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// ansiLen returns the length of the ANSI escape sequence beginning a
+// string. Zero is returned if the string does not begin with one.
+func ansiLen(s string) int {
+	if len(s) == 0 || s[0] != '\x1b' {
+		return 0
+	}
+
+	if len(s) == 1 {
+		return 1
+	}
+
+	switch s[1] {
+	case '[':
+		// Control sequence: parameter and intermediate bytes followed
+		// by a final byte
+		k := 2
+		for k < len(s) && (s[k] < 0x40 || 0x7e < s[k]) {
+			k++
+		}
+
+		if k < len(s) {
+			k++
+		}
+
+		return k
+	case ']':
+		// Operating system command: terminated by BEL or ST
+		k := 2
+		for k < len(s) && s[k] != '\a' && !(s[k] == '\x1b' && k+1 < len(s) && s[k+1] == '\\') {
+			k++
+		}
+
+		switch {
+		case len(s) <= k:
+			return k
+		case s[k] == '\a':
+			return k + 1
+		default:
+			return k + 2
+		}
+	default:
+		// Two-character sequence
+		return 2
+	}
+}
+
+// stripANSI returns a string with each ANSI escape sequence removed.
+func stripANSI(s string) string {
+	i := strings.IndexByte(s, '\x1b')
+	if i < 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for 0 <= i {
+		sb.WriteString(s[:i])
+		s = s[i+ansiLen(s[i:]):]
+		i = strings.IndexByte(s, '\x1b')
+	}
+
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// segments returns the grapheme clusters and ANSI escape sequences of a
+// string in order.
+func segments(s string) []segment {
+	segs := make([]segment, 0, len(s))
+	for 0 < len(s) {
+		if k := ansiLen(s); 0 < k {
+			segs = append(segs, segment{s: s[:k], esc: true})
+			s = s[k:]
+			continue
+		}
+
+		k := strings.IndexByte(s, '\x1b')
+		if k < 0 {
+			k = len(s)
+		}
+
+		g := uniseg.NewGraphemes(s[:k])
+		for g.Next() {
+			segs = append(segs, segment{s: g.Str(), w: widthCond.StringWidth(g.Str())})
+		}
+
+		s = s[k:]
+	}
+
+	return segs
+}
+
+// padding returns the spaces needed to pad a string to w cells.
+func padding(s string, w int) string {
+	if n := w - width(s); 0 < n {
+		return strings.Repeat(" ", n)
+	}
+
+	return ""
+}
+
+// rule returns a horizontal line w cells wide built by repeating a
+// string. If the string does not evenly fill the line, the line is
+// completed with as much of the string as fits followed by spaces.
+func rule(s string, w int) string {
+	sw := width(s)
+	if sw == 0 {
+		return strings.Repeat(s, w)
+	}
+
+	r := strings.Repeat(s, w/sw)
+	if rem := w % sw; 0 < rem {
+		part := widthCond.Truncate(stripANSI(s), rem, "")
+		r += part + strings.Repeat(" ", rem-width(part))
+	}
+
+	return r
+}
+
+// truncate returns a string cut to at most w cells. If cut, the string
+// ends with an ellipsis, unless the ellipsis is wider than w. Escape
+// sequences following the cut are kept so styles are still reset.
+func truncate(s string, w int, ellipsis string) string {
+	if width(s) <= w {
+		return s
+	}
+
+	ew := width(ellipsis)
+	if w < ew {
+		ellipsis, ew = "", 0
+	}
+
+	var (
+		sb   strings.Builder
+		tail strings.Builder
+		cw   int
+		cut  bool
+	)
+
+	for _, seg := range segments(s) {
+		switch {
+		case seg.esc && cut:
+			tail.WriteString(seg.s)
+		case seg.esc:
+			sb.WriteString(seg.s)
+		case !cut && cw+seg.w <= w-ew:
+			sb.WriteString(seg.s)
+			cw += seg.w
+		default:
+			cut = true
+		}
+	}
+
+	return sb.String() + ellipsis + tail.String()
+}
+
+// wrap returns the lines of a string broken between words so that each
+// line occupies at most w cells. Words are separated by white space and
+// words wider than w are broken wherever they must be. Consecutive white
+// space between words is collapsed into a single space.
+func wrap(s string, w int) []string {
+	if width(s) <= w {
+		return []string{s}
+	}
+
+	var (
+		lines []string
+		line  []segment
+		lw    int // Line width
+		word  []segment
+		ww    int // Word width
+	)
+
+	flushLine := func() {
+		var sb strings.Builder
+		for _, seg := range line {
+			sb.WriteString(seg.s)
+		}
+
+		lines = append(lines, sb.String())
+		line, lw = line[:0], 0
+	}
+
+	flushWord := func() {
+		if ww == 0 {
+			line = append(line, word...) // Escape sequences only
+			word = word[:0]
+			return
+		}
+
+		if 0 < lw && w < lw+1+ww {
+			flushLine()
+		}
+
+		if 0 < lw {
+			line = append(line, segment{s: " ", w: 1})
+			lw++
+		}
+
+		for _, seg := range word {
+			if !seg.esc && 0 < lw && w < lw+seg.w {
+				flushLine()
+			}
+
+			line = append(line, seg)
+			lw += seg.w
+		}
+
+		word, ww = word[:0], 0
+	}
+
+	for _, seg := range segments(s) {
+		if isSpace(seg) {
+			flushWord()
+			continue
+		}
+
+		word = append(word, seg)
+		ww += seg.w
+	}
+
+	flushWord()
+	if 0 < len(line) || len(lines) == 0 {
+		flushLine()
+	}
+
+	return lines
+}
+
+// isSpace determines if a segment is a single white space character.
+func isSpace(seg segment) bool {
+	r, size := utf8.DecodeRuneInString(seg.s)
+	return !seg.esc && size == len(seg.s) && unicode.IsSpace(r)
+}
@@ -0,0 +1,486 @@
+// Package xlsxtable reads tables from and writes tables to worksheets
+// of Excel (.xlsx) workbooks.
+//
+// The first row of a worksheet is the header. Booleans are read as
+// booleans, numbers as integers if every number in the column is
+// integral and as floats otherwise, numbers formatted as dates or times
+// as time stamps, and text as strings. A column mixing text with other
+// values is read as strings holding the raw text of each cell, such as
+// "1" for true and the serial number of a date. Empty cells are
+// missing.
+//
+// Time stamps are written as native date cells, the header is written
+// in bold, and each column is as wide as its widest value. Integers and
+// floats are both written as numbers. Floats holding only integral
+// values are therefore read back as integers.
+package xlsxtable
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nathangreene3/table"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// dateFmt and dateTimeFmt are the formats of time stamps read from
+	// cells formatted as dates or as dates and times.
+	dateFmt     = "2006-01-02"
+	dateTimeFmt = "2006-01-02 15:04:05"
+
+	// dateNumFmt and dateTimeNumFmt are the number formats of time
+	// stamp cells written without or with a time of day.
+	dateNumFmt     = "yyyy-mm-dd"
+	dateTimeNumFmt = "yyyy-mm-dd hh:mm:ss"
+
+	// maxColWidth is the greatest column width allowed by Excel.
+	maxColWidth = 255
+
+	// errVarCount indicates an unexpected number variadic arguments
+	// were provided.
+	errVarCount = "unexpected variadic argument provided"
+)
+
+// Options holds settings for reading or writing a worksheet.
+type Options struct {
+	// Sheet names the worksheet. If empty, the worksheet at Index is
+	// read, and "Sheet1" is written.
+	Sheet string
+
+	// Index is the zero-based position of the worksheet to read when
+	// Sheet is empty.
+	Index int
+}
+
+// FromXLSX returns a new table read from a worksheet of an Excel file.
+// A single set of options may be passed. This panics if more than one
+// set of options is provided.
+func FromXLSX(fileName string, opts ...Options) (*table.Table, error) {
+	f, err := excelize.OpenFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	return fromFile(f, options(opts))
+}
+
+// FromXLSXReader returns a new table read from a worksheet of an Excel
+// workbook. A single set of options may be passed. This panics if more
+// than one set of options is provided.
+func FromXLSXReader(r io.Reader, opts ...Options) (*table.Table, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	return fromFile(f, options(opts))
+}
+
+// options returns the set of options passed, if any.
+func options(opts []Options) Options {
+	switch len(opts) {
+	case 0:
+		return Options{}
+	case 1:
+		return opts[0]
+	default:
+		panic(errVarCount)
+	}
+}
+
+// fromFile returns a new table read from a worksheet of a workbook.
+func fromFile(f *excelize.File, opt Options) (*table.Table, error) {
+	sheet := opt.Sheet
+	if sheet == "" {
+		sheets := f.GetSheetList()
+		if opt.Index < 0 || len(sheets) <= opt.Index {
+			return nil, table.ErrRange
+		}
+
+		sheet = sheets[opt.Index]
+	}
+
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return table.New(nil), nil
+	}
+
+	props, err := f.GetWorkbookProps()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		date1904 = props.Date1904 != nil && *props.Date1904
+		h        = table.NewHeader(rows[0]...)
+		cols     = make([]table.Column, len(h))
+		raws     = make([]table.Column, len(h)) // Raw text of each cell
+	)
+
+	for i := 1; i < len(rows); i++ {
+		if len(h) < len(rows[i]) {
+			return nil, &table.Error{Err: table.ErrDims, Row: i - 1, Col: len(h), Type: table.Inv, Value: rows[i][len(h)]}
+		}
+
+		for j := 0; j < len(h); j++ {
+			if len(rows[i]) <= j || rows[i][j] == "" {
+				cols[j] = append(cols[j], table.Null)
+				raws[j] = append(raws[j], table.Null)
+				continue
+			}
+
+			cell, err := excelize.CoordinatesToCellName(j+1, i+1)
+			if err != nil {
+				return nil, err
+			}
+
+			v, err := cellValue(f, sheet, cell, rows[i][j], date1904)
+			if err != nil {
+				return nil, &table.Error{Err: err, Row: i - 1, Col: j, Name: h[j], Type: table.Inv, Value: rows[i][j]}
+			}
+
+			cols[j] = append(cols[j], v)
+			raws[j] = append(raws[j], rows[i][j])
+		}
+	}
+
+	t := table.New(nil)
+	for j := 0; j < len(h); j++ {
+		err := t.TryAppendCol(h[j], floats(cols[j]))
+		if errors.Is(err, table.ErrType) {
+			err = t.TryAppendCol(h[j], raws[j]) // Mixed types
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// cellValue returns the value of a cell given its raw value.
+func cellValue(f *excelize.File, sheet, cell, raw string, date1904 bool) (interface{}, error) {
+	ct, err := f.GetCellType(sheet, cell)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ct {
+	case excelize.CellTypeBool:
+		return raw == "1" || strings.EqualFold(raw, "true"), nil
+	case excelize.CellTypeDate:
+		return table.ParseFTime(raw)
+	case excelize.CellTypeUnset, excelize.CellTypeNumber:
+		style, err := f.GetCellStyle(sheet, cell)
+		if err != nil {
+			return nil, err
+		}
+
+		if date, hasTime := dateStyle(f, style); date {
+			x, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			tm, err := excelize.ExcelDateToTime(x, date1904)
+			if err != nil {
+				return nil, err
+			}
+
+			if hasTime {
+				return table.NewFTime(tm, dateTimeFmt), nil
+			}
+
+			return table.NewFTime(tm, dateFmt), nil
+		}
+
+		if x, err := strconv.Atoi(raw); err == nil {
+			return x, nil
+		}
+
+		return strconv.ParseFloat(raw, 64)
+	default:
+		return raw, nil
+	}
+}
+
+// floats returns a column of values converted to floats if the column
+// holds both integers and floats. Otherwise, the column is returned.
+func floats(c table.Column) table.Column {
+	var hasInt, hasFlt bool
+	for _, v := range c {
+		switch v.(type) {
+		case int:
+			hasInt = true
+		case float64:
+			hasFlt = true
+		}
+	}
+
+	if hasInt && hasFlt {
+		for i, v := range c {
+			if x, ok := v.(int); ok {
+				c[i] = float64(x)
+			}
+		}
+	}
+
+	return c
+}
+
+// dateStyle determines if a style formats numbers as dates, and if so,
+// whether it includes a time of day.
+func dateStyle(f *excelize.File, style int) (bool, bool) {
+	if f.Styles == nil || f.Styles.CellXfs == nil || style < 0 || len(f.Styles.CellXfs.Xf) <= style {
+		return false, false
+	}
+
+	id := f.Styles.CellXfs.Xf[style].NumFmtID
+	if id == nil {
+		return false, false
+	}
+
+	switch {
+	case *id == 14, 15 <= *id && *id <= 17, 27 <= *id && *id <= 31, 34 <= *id && *id <= 36, 50 <= *id && *id <= 58:
+		return true, false
+	case 18 <= *id && *id <= 22, *id == 32, *id == 33, 45 <= *id && *id <= 47:
+		return true, true
+	}
+
+	if f.Styles.NumFmts == nil {
+		return false, false
+	}
+
+	for _, nf := range f.Styles.NumFmts.NumFmt {
+		if nf != nil && nf.NumFmtID == *id {
+			return dateCode(nf.FormatCode)
+		}
+	}
+
+	return false, false
+}
+
+// dateCode determines if a number format code formats numbers as dates,
+// and if so, whether it includes a time of day. Quoted text, escaped
+// characters, and bracketed sections such as colors are ignored.
+func dateCode(code string) (bool, bool) {
+	var date, hasTime, quoted, bracketed, escaped bool
+	for _, r := range code {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted:
+			quoted = r != '"'
+		case bracketed:
+			bracketed = r != ']'
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = true
+		case r == '[':
+			bracketed = true
+		case r == ';':
+			return date || hasTime, hasTime // Only the first section formats positive numbers
+		default:
+			switch r {
+			case 'y', 'Y', 'd', 'D':
+				date = true
+			case 'h', 'H', 's', 'S':
+				hasTime = true
+			}
+		}
+	}
+
+	return date || hasTime, hasTime
+}
+
+// WriteXLSX writes a table to a worksheet of a new Excel file. A single
+// set of options may be passed. This panics if more than one set of
+// options is provided.
+func WriteXLSX(fileName string, t *table.Table, opts ...Options) error {
+	f, err := toFile(t, options(opts))
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	return f.SaveAs(fileName)
+}
+
+// WriteXLSXTo writes a table to a worksheet of a new Excel workbook. A
+// single set of options may be passed. This panics if more than one set
+// of options is provided.
+func WriteXLSXTo(w io.Writer, t *table.Table, opts ...Options) error {
+	f, err := toFile(t, options(opts))
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	_, err = f.WriteTo(w)
+	return err
+}
+
+// toFile returns a new workbook holding a table in one worksheet.
+// Non-finite floats are written as empty cells.
+func toFile(t *table.Table, opt Options) (*excelize.File, error) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	if opt.Sheet != "" && opt.Sheet != sheet {
+		if err := f.SetSheetName(sheet, opt.Sheet); err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		sheet = opt.Sheet
+	}
+
+	if err := writeSheet(f, sheet, t); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// writeSheet writes a table to a worksheet.
+func writeSheet(f *excelize.File, sheet string, t *table.Table) error {
+	var (
+		m, n = t.Dims()
+		h    = t.Header()
+		ts   = t.ColTypes()
+		ws   = make([]int, 0, n) // Column widths
+	)
+
+	if n == 0 {
+		return nil
+	}
+
+	if err := f.SetSheetRow(sheet, "A1", &h); err != nil {
+		return err
+	}
+
+	for j := 0; j < n; j++ {
+		ws = append(ws, table.DisplayWidth(h[j]))
+	}
+
+	r := make([]interface{}, n)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			switch v := t.Value(i, j).(type) {
+			case table.FTime:
+				r[j] = v.Time()
+			case float64:
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					r[j] = nil
+				} else {
+					r[j] = v
+				}
+			default:
+				if table.IsNull(v) {
+					r[j] = nil
+				} else {
+					r[j] = v
+				}
+			}
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+
+		if err := f.SetSheetRow(sheet, cell, &r); err != nil {
+			return err
+		}
+	}
+
+	// Widths are the display widths of the values as shown by
+	// Table.Format, so wide characters count twice.
+	ss := t.Strings()
+	for i := 1; i < len(ss); i++ {
+		for j := 0; j < n; j++ {
+			if w := table.DisplayWidth(ss[i][j]); ws[j] < w {
+				ws[j] = w
+			}
+		}
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	last, err := excelize.CoordinatesToCellName(n, 1)
+	if err != nil {
+		return err
+	}
+
+	if err := f.SetCellStyle(sheet, "A1", last, bold); err != nil {
+		return err
+	}
+
+	for j := 0; j < n; j++ {
+		col, err := excelize.ColumnNumberToName(j + 1)
+		if err != nil {
+			return err
+		}
+
+		if ts[j] == table.Time && 0 < m {
+			numFmt := dateNumFmt
+			if hasTime(t, j) {
+				numFmt = dateTimeNumFmt
+			}
+
+			if ws[j] < len(numFmt) {
+				ws[j] = len(numFmt)
+			}
+
+			style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &numFmt})
+			if err != nil {
+				return err
+			}
+
+			if err := f.SetCellStyle(sheet, col+"2", col+strconv.Itoa(m+1), style); err != nil {
+				return err
+			}
+		}
+
+		w := ws[j] + 2 // Padded by one space on each side
+		if maxColWidth < w {
+			w = maxColWidth
+		}
+
+		if err := f.SetColWidth(sheet, col, col, float64(w)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasTime determines if any time stamp in the jth column has a time of
+// day other than midnight.
+func hasTime(t *table.Table, j int) bool {
+	times, valid := t.ColNullTimes(j)
+	for i := 0; i < len(times); i++ {
+		if valid[i] {
+			if h, m, s := times[i].Clock(); h != 0 || m != 0 || s != 0 || times[i].Nanosecond() != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+package xlsxtable
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nathangreene3/table"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var (
+		when = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+		day  = time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+		exp  = table.New(
+			table.NewHeader("id", "score", "ok", "when", "day", "name", "none"),
+			table.NewRow(1, 1.5, true, table.NewFTime(when, dateTimeFmt), table.NewFTime(day, dateFmt), "a", table.Null),
+			table.NewRow(2, table.Null, false, table.Null, table.Null, table.Null, table.Null),
+			table.NewRow(table.Null, 2.0, table.Null, table.NewFTime(when, dateTimeFmt), table.NewFTime(day, dateFmt), "c", table.Null),
+		)
+	)
+
+	var buf bytes.Buffer
+	if err := WriteXLSXTo(&buf, exp, Options{Sheet: "data"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := FromXLSXReader(bytes.NewReader(buf.Bytes()), Options{Sheet: "data"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	if rec, err = FromXLSXReader(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+
+	if _, err := FromXLSXReader(bytes.NewReader(buf.Bytes()), Options{Index: 1}); !errors.Is(err, table.ErrRange) {
+		t.Fatalf("\nexpected %v\nreceived %v\n", table.ErrRange, err)
+	}
+
+	// Styles and widths
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+	style, err := f.GetCellStyle("data", "A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xf := f.Styles.CellXfs.Xf[style]
+	if xf.FontID == nil {
+		t.Fatal("expected a bold header")
+	}
+
+	if b := f.Styles.Fonts.Font[*xf.FontID].B; b == nil || (b.Val != nil && !*b.Val) {
+		t.Fatal("expected a bold header")
+	}
+
+	for col, exp := range map[string]float64{"A": 4, "D": 21, "E": 12, "F": 6} {
+		if rec, err := f.GetColWidth("data", col); err != nil {
+			t.Fatal(err)
+		} else if exp != rec {
+			t.Fatalf("\nexpected width of %s to be %v\nreceived %v\n", col, exp, rec)
+		}
+	}
+}
+
+func TestColWidths(t *testing.T) {
+	var (
+		tbl = table.New(
+			table.NewHeader("名前", "b"),
+			table.NewRow("日本語\nab", "\x1b[1mbold\x1b[0m"),
+		)
+		buf bytes.Buffer
+	)
+
+	if err := WriteXLSXTo(&buf, tbl); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+	for col, exp := range map[string]float64{"A": 8, "B": 6} {
+		if rec, err := f.GetColWidth("Sheet1", col); err != nil {
+			t.Fatal(err)
+		} else if exp != rec {
+			t.Fatalf("\nexpected width of %s to be %v\nreceived %v\n", col, exp, rec)
+		}
+	}
+}
+
+func TestMixedTypes(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	cells := map[string]interface{}{
+		"A1": "mixed", "B1": "numbers",
+		"A2": "a", "B2": 1,
+		"A3": 2.5, "B3": 2.5,
+		"A4": true,
+		"A5": time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), "B5": 3,
+	}
+
+	for cell, v := range cells {
+		if err := f.SetCellValue("Sheet1", cell, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := FromXLSXReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := table.New(
+		table.NewHeader("mixed", "numbers"),
+		table.NewRow("a", 1.0),
+		table.NewRow("2.5", 2.5),
+		table.NewRow("1", table.Null),
+		table.NewRow("44259", 3.0),
+	)
+
+	if !exp.Equal(rec) {
+		t.Fatalf("\nexpected\n%s\nreceived\n%s\n", exp, rec)
+	}
+}
+
+func TestDateCode(t *testing.T) {
+	tests := []struct {
+		code          string
+		date, hasTime bool
+	}{
+		{code: "0.00"},
+		{code: `#,##0 "days"`},
+		{code: "[Red]0.0"},
+		{code: "dd/mm/yyyy", date: true},
+		{code: "yyyy-mm-dd hh:mm", date: true, hasTime: true},
+		{code: `0\d`},
+		{code: "0;[Red]d"},
+	}
+
+	for _, test := range tests {
+		if date, hasTime := dateCode(test.code); test.date != date || test.hasTime != hasTime {
+			t.Fatalf("\n%q: expected %t, %t\nreceived %t, %t\n", test.code, test.date, test.hasTime, date, hasTime)
+		}
+	}
+}